@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// beginAfterimage redirects drawing to the persistent afterimage texture and
+// fades what was drawn there before towards the background color, leaving
+// faint ghosts of moving things. It reports false, turning the effect off,
+// if the texture can't be used.
+func (g *Game) beginAfterimage() bool {
+	if err := g.startAfterimage(); err != nil {
+		slog.Warn("could not draw afterimages, turning afterimage off", "err", err)
+		g.afterimage = false
+		g.renderer.SetRenderTarget(nil)
+		return false
+	}
+
+	r, gr, b, a, _ := g.renderer.GetDrawColor()
+	var mode sdl.BlendMode
+	g.renderer.GetDrawBlendMode(&mode)
+	defer g.renderer.SetDrawColor(r, gr, b, a)
+	defer g.renderer.SetDrawBlendMode(mode)
+
+	c := g.backgroundColor()
+	g.renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+	g.renderer.SetDrawColor(c.R, c.G, c.B, g.cfg.AfterimageFade)
+	g.renderer.FillRect(nil)
+	return true
+}
+
+// startAfterimage makes the afterimage texture the render target, creating
+// it filled with the background color the first time.
+func (g *Game) startAfterimage() error {
+	fresh := g.accum == nil
+	if fresh {
+		t, err := g.renderer.CreateTexture(sdl.PIXELFORMAT_ARGB8888, sdl.TEXTUREACCESS_TARGET, g.width, g.height)
+		if err != nil {
+			return fmt.Errorf("Error creating afterimage texture: %v", err)
+		}
+		g.accum = t
+	}
+	if err := g.renderer.SetRenderTarget(g.accum); err != nil {
+		return fmt.Errorf("Error drawing to afterimage texture: %v", err)
+	}
+	if fresh {
+		g.clear()
+	}
+	return nil
+}
+
+// endAfterimage draws the afterimage texture to the window, so whatever is
+// drawn after it isn't ghosted.
+func (g *Game) endAfterimage() {
+	g.renderer.SetRenderTarget(nil)
+	g.renderer.Copy(g.accum, nil, nil)
+}
+
+// freeAfterimage drops the afterimage texture while the effect is off, so
+// turning it back on starts from a clean frame.
+func (g *Game) freeAfterimage() {
+	if g.accum != nil {
+		g.accum.Destroy()
+		g.accum = nil
+	}
+}
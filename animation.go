@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Animation plays the frames of a horizontal sprite sheet: frames images of
+// the same size side by side in one texture, shown fps times a second and
+// looping. A sheet of one frame is a still image.
+type Animation struct {
+	texture        *sdl.Texture
+	frameW, frameH int32
+	frames         int
+	fps            float64
+	elapsed        float64 // seconds into the current loop
+}
+
+// NewAnimation splits tex into frames equally wide frames.
+func NewAnimation(tex *sdl.Texture, frames int, fps float64) (*Animation, error) {
+	_, _, w, h, err := tex.Query()
+	if err != nil {
+		return nil, err
+	}
+	if int32(frames) > w {
+		return nil, fmt.Errorf("%d frames don't fit in a %d pixel wide sheet", frames, w)
+	}
+	return &Animation{texture: tex, frameW: w / int32(frames), frameH: h, frames: frames, fps: fps}, nil
+}
+
+// Update advances the animation by dt seconds.
+func (a *Animation) Update(dt float64) {
+	if a.frames <= 1 || a.fps <= 0 {
+		return
+	}
+	a.elapsed = math.Mod(a.elapsed+dt, float64(a.frames)/a.fps)
+}
+
+// Frame is the index of the frame showing, from 0.
+func (a *Animation) Frame() int {
+	if a.fps <= 0 {
+		return 0
+	}
+	return min(int(a.elapsed*a.fps), a.frames-1)
+}
+
+// CurrentSrcRect is the part of the sheet to draw for the current frame.
+func (a *Animation) CurrentSrcRect() *sdl.Rect {
+	return &sdl.Rect{X: int32(a.Frame()) * a.frameW, W: a.frameW, H: a.frameH}
+}
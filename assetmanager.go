@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/veandco/go-sdl2/img"
+	"github.com/veandco/go-sdl2/mix"
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+// Kinds of asset the AssetManager loads.
+const (
+	assetTexture = "texture"
+	assetSurface = "surface"
+	assetFont    = "font"
+	assetSound   = "sound"
+	assetMusic   = "music"
+)
+
+type assetKey struct {
+	kind, name string
+}
+
+// loadedAsset is an asset the manager owns. value is an *sdl.Texture,
+// *sdl.Surface, *ttf.Font, *mix.Chunk or *mix.Music depending on kind, and
+// size the point size of a font. data is the file it was read from, for
+// fonts and music, which keep reading it for as long as they are loaded.
+type loadedAsset struct {
+	kind  string
+	name  string
+	path  string
+	size  int
+	value any
+	data  []byte
+}
+
+// AssetManager loads images, fonts, sounds and music, keeping each under a
+// name so that loading the same name again returns the one already loaded.
+// Everything it loaded is freed by Destroy, which has to run before the
+// renderer is destroyed and SDL shut down. Load errors are returned as they
+// come from SDL for the caller to put in context. Files are read through
+// readAsset, from the bundle when there is one.
+type AssetManager struct {
+	renderer *sdl.Renderer
+	bundle   *Bundle
+	assets   []*loadedAsset // in load order
+	byKey    map[assetKey]*loadedAsset
+}
+
+// NewAssetManager makes a manager loading from bundle, which may be nil to
+// load from the filesystem only.
+func NewAssetManager(renderer *sdl.Renderer, bundle *Bundle) *AssetManager {
+	return &AssetManager{renderer: renderer, bundle: bundle, byKey: make(map[assetKey]*loadedAsset)}
+}
+
+// load returns the asset of the given kind and name, opening it from path
+// with open the first time it is asked for. open also returns the data the
+// asset has to keep, if any.
+func (m *AssetManager) load(kind, name, path string, size int, open func() (any, []byte, error)) (any, error) {
+	if a, ok := m.byKey[assetKey{kind, name}]; ok {
+		if a.path != path || a.size != size {
+			slog.Warn("asset already loaded from elsewhere, keeping it", "kind", kind, "name", name, "loaded", a.path, "asked", path)
+		}
+		return a.value, nil
+	}
+	v, data, err := open()
+	if err != nil {
+		return nil, err
+	}
+	a := &loadedAsset{kind: kind, name: name, path: path, size: size, value: v, data: data}
+	m.assets = append(m.assets, a)
+	m.byKey[assetKey{kind, name}] = a
+	return v, nil
+}
+
+func (m *AssetManager) get(kind, name string) any {
+	if a, ok := m.byKey[assetKey{kind, name}]; ok {
+		return a.value
+	}
+	return nil
+}
+
+// LoadTexture loads an image as a texture, making its color key
+// transparent if it has one.
+func (m *AssetManager) LoadTexture(name string, ic ImageConfig) (*sdl.Texture, error) {
+	v, err := m.load(assetTexture, name, ic.Path, 0, func() (any, []byte, error) {
+		t, err := m.openTexture(ic)
+		return t, nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*sdl.Texture), nil
+}
+
+func (m *AssetManager) openTexture(ic ImageConfig) (*sdl.Texture, error) {
+	if ic.ColorKey == nil {
+		src, _, err := m.openAsset(ic.Path)
+		if err != nil {
+			return nil, err
+		}
+		return img.LoadTextureRW(m.renderer, src, true)
+	}
+	surface, err := m.OpenSurface(ic.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer surface.Free()
+	key := sdl.MapRGB(surface.Format, ic.ColorKey.R, ic.ColorKey.G, ic.ColorKey.B)
+	if err := surface.SetColorKey(true, key); err != nil {
+		return nil, fmt.Errorf("setting color key: %v", err)
+	}
+	return m.renderer.CreateTextureFromSurface(surface)
+}
+
+// LoadSurface loads an image as a surface, for when it isn't drawn with the
+// renderer, such as the window icon.
+func (m *AssetManager) LoadSurface(name, path string) (*sdl.Surface, error) {
+	v, err := m.load(assetSurface, name, path, 0, func() (any, []byte, error) {
+		s, err := m.OpenSurface(path)
+		return s, nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*sdl.Surface), nil
+}
+
+// OpenSurface loads an image as a surface that the manager doesn't keep,
+// for the caller to free once done with it.
+func (m *AssetManager) OpenSurface(path string) (*sdl.Surface, error) {
+	src, _, err := m.openAsset(path)
+	if err != nil {
+		return nil, err
+	}
+	return img.LoadRW(src, true)
+}
+
+func (m *AssetManager) LoadFont(name, path string, size int) (*ttf.Font, error) {
+	v, err := m.load(assetFont, name, path, size, func() (any, []byte, error) {
+		src, data, err := m.openAsset(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		f, err := ttf.OpenFontRW(src, 1, size)
+		return f, data, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*ttf.Font), nil
+}
+
+func (m *AssetManager) LoadSound(name, path string) (*mix.Chunk, error) {
+	v, err := m.load(assetSound, name, path, 0, func() (any, []byte, error) {
+		src, _, err := m.openAsset(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		c, err := mix.LoadWAVRW(src, true)
+		return c, nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*mix.Chunk), nil
+}
+
+func (m *AssetManager) LoadMusic(name, path string) (*mix.Music, error) {
+	v, err := m.load(assetMusic, name, path, 0, func() (any, []byte, error) {
+		src, data, err := m.openAsset(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		mus, err := mix.LoadMUSRW(src, 1)
+		return mus, data, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*mix.Music), nil
+}
+
+// LoadSoundData loads a sound from WAV data made in memory. path is only
+// kept for the asset log, as where the data came from.
+func (m *AssetManager) LoadSoundData(name, path string, wav []byte) (*mix.Chunk, error) {
+	v, err := m.load(assetSound, name, path, 0, func() (any, []byte, error) {
+		src, err := sdl.RWFromMem(wav)
+		if err != nil {
+			return nil, nil, err
+		}
+		c, err := mix.LoadWAVRW(src, true)
+		return c, nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*mix.Chunk), nil
+}
+
+// SoundSlice makes a sound of part of the samples of another, which have to
+// be in the audio device's format. The samples aren't copied, so the sound
+// they come from has to be loaded first and be freed after this one, as
+// Destroy does.
+func (m *AssetManager) SoundSlice(name, path string, samples []byte) (*mix.Chunk, error) {
+	v, err := m.load(assetSound, name, path, 0, func() (any, []byte, error) {
+		c, err := mix.QuickLoadRAW(sliceData(samples), uint32(len(samples)))
+		return c, nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*mix.Chunk), nil
+}
+
+// SolidTexture makes a texture of one color, to stand in for an image that
+// couldn't be loaded.
+func (m *AssetManager) SolidTexture(name string, w, h int32, c sdl.Color) (*sdl.Texture, error) {
+	v, err := m.load(assetTexture, name, "", 0, func() (any, []byte, error) {
+		surface, err := sdl.CreateRGBSurfaceWithFormat(0, w, h, 32, sdl.PIXELFORMAT_ARGB8888)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer surface.Free()
+		surface.FillRect(nil, sdl.MapRGBA(surface.Format, c.R, c.G, c.B, c.A))
+		t, err := m.renderer.CreateTextureFromSurface(surface)
+		return t, nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*sdl.Texture), nil
+}
+
+// openAsset reads the file at path with readAsset into an RWops for SDL to
+// load from, which frees it when done. The data has to be kept for as long
+// as whatever is loaded keeps reading from it.
+func (m *AssetManager) openAsset(path string) (*sdl.RWops, []byte, error) {
+	data, err := m.readAsset(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	src, err := sdl.RWFromMem(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return src, data, nil
+}
+
+// Font returns the font loaded under name, or nil if there is none.
+func (m *AssetManager) Font(name string) *ttf.Font {
+	f, _ := m.get(assetFont, name).(*ttf.Font)
+	return f
+}
+
+// Sound returns the sound loaded under name, or nil if there is none.
+func (m *AssetManager) Sound(name string) *mix.Chunk {
+	c, _ := m.get(assetSound, name).(*mix.Chunk)
+	return c
+}
+
+// Free frees every asset of the given kind, so that they can be loaded
+// again differently.
+func (m *AssetManager) Free(kind string) {
+	m.assets = slices.DeleteFunc(m.assets, func(a *loadedAsset) bool {
+		if a.kind != kind {
+			return false
+		}
+		freeAsset(a)
+		delete(m.byKey, assetKey{a.kind, a.name})
+		return true
+	})
+}
+
+// Destroy frees everything the manager loaded, newest first. Playing
+// sounds and music have to be halted before.
+func (m *AssetManager) Destroy() {
+	for i := len(m.assets) - 1; i >= 0; i-- {
+		freeAsset(m.assets[i])
+	}
+	m.assets = nil
+	clear(m.byKey)
+}
+
+func freeAsset(a *loadedAsset) {
+	switch v := a.value.(type) {
+	case *sdl.Texture:
+		v.Destroy()
+	case *sdl.Surface:
+		v.Free()
+	case *ttf.Font:
+		v.Close()
+	case *mix.Chunk:
+		v.Free()
+	case *mix.Music:
+		v.Free()
+	}
+}
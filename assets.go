@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/veandco/go-sdl2/mix"
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+// assetInfo describes a loaded asset for logAssets. bytes is a rough
+// estimate of the memory it holds, 0 when unknown.
+type assetInfo struct {
+	kind   string
+	name   string
+	path   string
+	detail string
+	bytes  int
+}
+
+// loadedAssets lists every texture, surface, font, sound and music track
+// the game currently holds: those the asset manager loaded, then the ones
+// made while running.
+func (g *Game) loadedAssets() []assetInfo {
+	var assets []assetInfo
+	addTexture := func(name, path string, t *sdl.Texture) {
+		if t == nil {
+			return
+		}
+		format, _, w, h, err := t.Query()
+		if err != nil {
+			slog.Warn("could not query texture", "name", name, "err", err)
+			return
+		}
+		assets = append(assets, assetInfo{
+			kind:   "texture",
+			name:   name,
+			path:   path,
+			detail: fmt.Sprintf("%dx%d", w, h),
+			bytes:  int(w) * int(h) * sdl.BytesPerPixel(format),
+		})
+	}
+	addSurface := func(name, path string, s *sdl.Surface) {
+		assets = append(assets, assetInfo{kind: "surface", name: name, path: path, detail: fmt.Sprintf("%dx%d", s.W, s.H), bytes: int(s.Pitch) * int(s.H)})
+	}
+
+	bytesPerMs := 0.0
+	if freq, format, channels, _, err := mix.QuerySpec(); err == nil {
+		bytesPerMs = float64(freq) * float64(channels) * float64(format&0xFF) / 8 / 1000
+	}
+	for _, a := range g.assets.assets {
+		switch v := a.value.(type) {
+		case *sdl.Texture:
+			addTexture(a.name, a.path, v)
+		case *sdl.Surface:
+			addSurface(a.name, a.path, v)
+		case *ttf.Font:
+			assets = append(assets, assetInfo{kind: "font", name: a.name, path: a.path, detail: fmt.Sprintf("%dpt", a.size)})
+		case *mix.Chunk:
+			ms := v.LengthInMs()
+			assets = append(assets, assetInfo{kind: "sound", name: a.name, path: a.path, detail: fmt.Sprintf("%dms", ms), bytes: int(float64(ms) * bytesPerMs)})
+		case *mix.Music:
+			assets = append(assets, assetInfo{kind: "music", name: a.name, path: a.path, detail: "streamed"})
+		}
+	}
+
+	addTexture("title", "", g.text)
+	addTexture(g.effectName+" effect", "", g.effect)
+	keys := make([]blurKey, 0, len(g.blur.textures))
+	for k := range g.blur.textures {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i].rect, keys[j].rect
+		return a.Y < b.Y || a.Y == b.Y && a.X < b.X
+	})
+	for _, k := range keys {
+		addTexture(fmt.Sprintf("blurred panel at %d,%d", k.rect.X, k.rect.Y), "", g.blur.textures[k])
+	}
+	if s := g.blur.source; s != nil {
+		addSurface("blur source", g.backgroundPath(), s)
+	}
+	return assets
+}
+
+// logAssets logs every loaded asset with its size and a total estimate of
+// their memory use, warning about any file loaded more than once the same
+// way.
+func (g *Game) logAssets() {
+	assets := g.loadedAssets()
+	total := 0
+	seen := make(map[assetInfo]int)
+	for _, a := range assets {
+		slog.Info("asset", "kind", a.kind, "name", a.name, "path", a.path, "size", a.detail, "kb", a.bytes/1024)
+		total += a.bytes
+		if a.path != "" {
+			seen[assetInfo{kind: a.kind, path: a.path, detail: a.detail}]++
+		}
+	}
+	for a, n := range seen {
+		if n > 1 {
+			slog.Warn("asset loaded more than once", "kind", a.kind, "path", a.path, "size", a.detail, "times", n)
+		}
+	}
+	slog.Info("assets loaded", "count", len(assets), "kb", total/1024)
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/veandco/go-sdl2/mix"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Game events that play a sound, used as keys of the sounds setting.
+const (
+	soundAction = "action"
+	soundBounce = "bounce"
+)
+
+// What playSound does when every mixer channel is busy.
+const (
+	soundPolicyDrop  = "drop"
+	soundPolicySteal = "steal"
+)
+
+// How long the music fades in at start and out on quitting, in ms.
+const (
+	musicFadeInMillis  = 1000
+	musicFadeOutMillis = 800
+)
+
+// With speedReactiveAudio a bounce at impactFullSpeed pixels per second or
+// faster plays at full volume, slower ones down to impactMinVolume of it.
+const (
+	impactFullSpeed = 500.0
+	impactMinVolume = 0.4
+)
+
+// audioFormats are the sample formats the audio setting can ask for, all
+// in native byte order.
+var audioFormats = map[string]uint16{
+	"u8":  sdl.AUDIO_U8,
+	"s8":  sdl.AUDIO_S8,
+	"u16": sdl.AUDIO_U16SYS,
+	"s16": sdl.AUDIO_S16SYS,
+	"s32": sdl.AUDIO_S32SYS,
+	"f32": sdl.AUDIO_F32SYS,
+}
+
+// AudioConfig is how the audio device is opened. Frequency is in Hz and
+// ChunkSize in sample frames; smaller chunks lower the latency of sounds
+// but take more CPU to keep fed.
+type AudioConfig struct {
+	Frequency int    `json:"frequency"`
+	Format    string `json:"format"`
+	Channels  int    `json:"channels"`
+	ChunkSize int    `json:"chunkSize"`
+}
+
+func defaultAudioConfig() AudioConfig {
+	return AudioConfig{Frequency: mix.DEFAULT_FREQUENCY, Format: "s16", Channels: mix.DEFAULT_CHANNELS, ChunkSize: mix.DEFAULT_CHUNKSIZE}
+}
+
+func validateAudio(a AudioConfig) error {
+	switch a.Frequency {
+	case 11025, 22050, 44100, 48000, 96000:
+	default:
+		return fmt.Errorf("audio: frequency must be 11025, 22050, 44100, 48000 or 96000, got %d", a.Frequency)
+	}
+	if _, ok := audioFormats[a.Format]; !ok {
+		return fmt.Errorf("audio: format must be u8, s8, u16, s16, s32 or f32, got %q", a.Format)
+	}
+	switch a.Channels {
+	case 1, 2, 4, 6:
+	default:
+		return fmt.Errorf("audio: channels must be 1, 2, 4 or 6, got %d", a.Channels)
+	}
+	if a.ChunkSize < 64 || a.ChunkSize > 8192 || a.ChunkSize&(a.ChunkSize-1) != 0 {
+		return fmt.Errorf("audio: chunkSize must be a power of two from 64 to 8192, got %d", a.ChunkSize)
+	}
+	return nil
+}
+
+// openAudio opens the audio device as configured, or with the defaults if
+// the device won't take that, and logs what it actually got.
+func (g *Game) openAudio() error {
+	a := g.cfg.Audio
+	err := mix.OpenAudio(a.Frequency, audioFormats[a.Format], a.Channels, a.ChunkSize)
+	if err != nil && a != defaultAudioConfig() {
+		slog.Warn("audio device rejected the audio settings, using the defaults", "err", err)
+		a = defaultAudioConfig()
+		err = mix.OpenAudio(a.Frequency, audioFormats[a.Format], a.Channels, a.ChunkSize)
+	}
+	if err != nil {
+		return fmt.Errorf("Error initializing SDL_mixer audio: %v", err)
+	}
+	freq, format, channels, _, err := mix.QuerySpec()
+	if err != nil {
+		slog.Warn("could not query audio spec", "err", err)
+		return nil
+	}
+	slog.Info("audio opened", "frequency", freq, "format", audioFormatName(format), "channels", channels, "chunkSize", a.ChunkSize)
+	return nil
+}
+
+// audioFormatName is the audio setting's name for format, or its number if
+// it has none.
+func audioFormatName(format uint16) string {
+	for name, f := range audioFormats {
+		if f == format {
+			return name
+		}
+	}
+	return fmt.Sprintf("%#x", format)
+}
+
+// SoundConfig is the sound played for a game event. Loops is how many times
+// it repeats after playing once, -1 repeating until stopped.
+type SoundConfig struct {
+	Path  string `json:"path"`
+	Loops int    `json:"loops"`
+}
+
+// MusicTrack is an entry of the music playlist. Loops works as for sounds,
+// so a track with -1 plays forever and the playlist never moves past it.
+// LoopStart and LoopEnd, in seconds, make the track play up to its end, or
+// LoopEnd if set, once and then repeat from LoopStart, ignoring Loops.
+type MusicTrack struct {
+	Path      string  `json:"path"`
+	Loops     int     `json:"loops"`
+	LoopStart float64 `json:"loopStart"`
+	LoopEnd   float64 `json:"loopEnd"`
+}
+
+func validateSounds(sounds map[string]SoundConfig) error {
+	for name, s := range sounds {
+		switch name {
+		case soundAction, soundBounce:
+		default:
+			return fmt.Errorf("sounds: unknown event %q, expected %q or %q", name, soundAction, soundBounce)
+		}
+		if s.Path == "" {
+			return fmt.Errorf("sounds: %q needs a path", name)
+		}
+		if s.Loops < -1 {
+			return fmt.Errorf("sounds: loops of %q must be -1 (forever) or more, got %d", name, s.Loops)
+		}
+	}
+	return nil
+}
+
+func validateMusic(tracks []MusicTrack) error {
+	for i, t := range tracks {
+		if t.Path == "" {
+			return fmt.Errorf("music: track %d needs a path", i+1)
+		}
+		if t.Loops < -1 {
+			return fmt.Errorf("music: loops of track %d must be -1 (forever) or more, got %d", i+1, t.Loops)
+		}
+		if err := validateLoopRegion(i, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reservedChannels are the mixer channels kept for musicChannel and
+// bounceChannel, numbered before those other sounds play on.
+const reservedChannels = 2
+
+// allocateChannels sets the number of mixer channels sounds can play on at
+// once, besides the reserved ones. Channels beyond n are stopped.
+func (g *Game) allocateChannels(n int) {
+	if g.opts.NoAudio {
+		return
+	}
+	mix.AllocateChannels(n + reservedChannels)
+	mix.ReserveChannels(reservedChannels)
+	starts := make([]uint32, n+reservedChannels)
+	copy(starts, g.channelStarts)
+	g.channelStarts = starts
+}
+
+// loadAudio loads the sounds under the name of their event and the music
+// under its path, so a track listed twice is only loaded once. A sound that
+// fails to load is replaced with silence and a track that fails is left out
+// of the playlist.
+func (g *Game) loadAudio() {
+	for name, s := range g.cfg.Sounds {
+		if _, err := g.assets.LoadSound(name, s.Path); err != nil {
+			g.assetFailed(fmt.Errorf("Error loading sound chunk %q: %v", name, err))
+			if _, err := g.loadSilentSound(name); err != nil {
+				g.assetFailed(fmt.Errorf("Error creating silent sound: %v", err))
+			}
+		}
+	}
+	for _, t := range g.cfg.Music {
+		music, err := g.assets.LoadMusic(t.Path, t.Path)
+		if err != nil {
+			g.assetFailed(fmt.Errorf("Error loading music: %v", err))
+			continue
+		}
+		var looped *loopedTrack
+		if t.hasLoopRegion() {
+			if looped, err = g.loadLoopedTrack(t); err != nil {
+				g.assetFailed(fmt.Errorf("Error loading loop region of %s, playing it whole: %v", t.Path, err))
+			}
+		}
+		g.playlist = append(g.playlist, music)
+		g.loopedTracks = append(g.loopedTracks, looped)
+	}
+	if g.cfg.PitchVariation {
+		g.makeBounceVariants()
+	}
+}
+
+// playSound plays the sound configured for event on the first free channel.
+// When all channels are busy the sound is dropped, or with the steal policy
+// replaces the one that started longest ago. It is a no-op when audio is
+// disabled or the event has no sound.
+func (g *Game) playSound(event string) {
+	g.playChunk(event, g.assets.Sound(event), g.volume)
+}
+
+// impactLevel maps the speed of a bounce, in pixels per second, to 0 for
+// standing still up to 1 for impactFullSpeed and faster.
+func impactLevel(speed float64) float64 {
+	return clampf(speed/impactFullSpeed, 0, 1)
+}
+
+// playBounceSound plays the bounce sound for an impact at speed, x across
+// the window. With speedReactiveAudio faster impacts are louder and, when
+// there are pitch variants, higher.
+func (g *Game) playBounceSound(speed, x float64) {
+	if !g.cfg.SpeedReactiveAudio {
+		g.playPannedBounce(g.bounceChunk(), g.volume, x)
+		return
+	}
+	level := impactLevel(speed)
+	chunk := g.assets.Sound(soundBounce)
+	if n := len(g.bounceVariants); n > 0 {
+		chunk = g.bounceVariants[int(math.Round(level*float64(n-1)))]
+	}
+	g.playPannedBounce(chunk, int(float64(g.volume)*lerp(impactMinVolume, 1, level)), x)
+}
+
+// playChunk plays chunk for event at volume, as playSound describes.
+func (g *Game) playChunk(event string, chunk *mix.Chunk, volume int) {
+	if chunk == nil {
+		return
+	}
+	loops := g.cfg.Sounds[event].Loops
+	channel, err := chunk.Play(-1, loops)
+	if err != nil && g.cfg.SoundPolicy == soundPolicySteal && len(g.channelStarts) > 0 {
+		oldest := reservedChannels
+		for i, start := range g.channelStarts[oldest:] {
+			if start < g.channelStarts[oldest] {
+				oldest = reservedChannels + i
+			}
+		}
+		mix.HaltChannel(oldest)
+		channel, err = chunk.Play(oldest, loops)
+	}
+	if err != nil {
+		slog.Debug("sound dropped", "event", event, "err", err)
+		return
+	}
+	// Channels keep their volume, so set it for every sound.
+	mix.Volume(channel, volume)
+	if channel < len(g.channelStarts) {
+		g.channelStarts[channel] = sdl.GetTicks()
+	}
+}
+
+// playTrack starts track i of the playlist, fading it in over fadeMs
+// milliseconds if that isn't 0. If it fails to play the playlist stops
+// there.
+func (g *Game) playTrack(i, fadeMs int) {
+	if len(g.playlist) == 0 {
+		return
+	}
+	g.track = i % len(g.playlist)
+	g.music = g.playlist[g.track]
+	if l := g.loopedTracks[g.track]; l != nil {
+		if err := g.playLooped(l, fadeMs); err != nil {
+			slog.Error("could not play music", "track", g.cfg.Music[g.track].Path, "err", err)
+			g.music = nil
+			return
+		}
+		slog.Debug("playing music with a loop region", "track", g.cfg.Music[g.track].Path)
+		return
+	}
+	g.looping = nil
+	// Mix_PlayMusic counts plays rather than repeats, and treats 0 as 1.
+	loops := g.cfg.Music[g.track].Loops
+	if loops >= 0 {
+		loops++
+	}
+	play := func() error { return g.music.Play(loops) }
+	if fadeMs > 0 {
+		play = func() error { return g.music.FadeIn(loops, fadeMs) }
+	}
+	if err := play(); err != nil {
+		slog.Error("could not play music", "track", g.cfg.Music[g.track].Path, "err", err)
+		g.music = nil
+		return
+	}
+	slog.Debug("playing music", "track", g.cfg.Music[g.track].Path)
+}
+
+// updateMusic moves on to the next track when the current one is done.
+func (g *Game) updateMusic() {
+	g.updateLoopedMusic()
+	if g.music != nil && !g.musicPlaying() {
+		g.playTrack(g.track+1, 0)
+	}
+}
+
+// fadeOutMusic fades the music out over musicFadeOutMillis and waits for it
+// to finish.
+func (g *Game) fadeOutMusic() {
+	if g.opts.NoAudio || g.music == nil || !g.musicPlaying() || g.musicPaused() {
+		return
+	}
+	if g.looping != nil {
+		mix.FadeOutChannel(musicChannel, musicFadeOutMillis)
+	} else {
+		mix.FadeOutMusic(musicFadeOutMillis)
+	}
+	g.waitMusicFade()
+}
+
+// waitMusicFade waits for a fade of the music in progress to finish, giving
+// up a little after it should have.
+func (g *Game) waitMusicFade() {
+	deadline := sdl.GetTicks() + musicFadeOutMillis + 200
+	for g.musicFading() && g.musicPlaying() && sdl.GetTicks() < deadline {
+		sdl.Delay(10)
+	}
+}
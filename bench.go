@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// benchSprites is how many sprites are spawned for -bench-frames, so the
+// frames drawn have something to draw besides the background and title.
+const benchSprites = 100
+
+// startBench sets up a -bench-frames run.
+func (g *Game) startBench() {
+	if g.frameTimes == nil {
+		g.frameTimes = newFrameTimeLog("")
+	}
+	for i := 0; i < benchSprites; i++ {
+		g.spawnSprite()
+	}
+}
+
+// fillBenchParticles bursts a whole pool of particles at the middle of the
+// screen, so every -bench-frames frame updates and draws as many as can be
+// alive at once.
+func (g *Game) fillBenchParticles() {
+	g.particles.Spawn(g.width/2, g.height/2, g.cfg.MaxParticles, g.cfg.TextColor)
+}
+
+// benchDone reports whether the -bench-frames run has drawn all its frames,
+// printing the results if so.
+func (g *Game) benchDone() bool {
+	if g.frameCount < uint64(g.opts.BenchFrames) {
+		return false
+	}
+	times := g.frameTimes.Samples()
+	if len(times) == 0 {
+		fmt.Printf("bench: %d frames, too few to time\n", g.frameCount)
+		return true
+	}
+	sum := 0.0
+	for _, ms := range times {
+		sum += ms
+	}
+	slices.Sort(times)
+	fmt.Printf("bench: %d frames, avg %.3f ms, min %.3f ms, max %.3f ms, p99 %.3f ms\n",
+		g.frameCount, sum/float64(len(times)), times[0], times[len(times)-1], percentile(times, 99))
+	return true
+}
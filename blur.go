@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// The frosted backdrop is blurred at a fraction of the panel's size, which
+// keeps the software blur cheap, and scaled back up with linear filtering.
+const (
+	hudBlurScale  = 4
+	hudBlurRadius = 2
+	hudBlurPasses = 2
+)
+
+type blurKey struct {
+	rect   sdl.Rect
+	radius int32
+}
+
+// blurCache keeps the frosted backdrops made for each panel. They only depend
+// on the background image and the clear color showing through it, so they
+// are rebuilt when either changes.
+type blurCache struct {
+	background *sdl.Texture
+	color      sdl.Color
+	source     *sdl.Surface
+	textures   map[blurKey]*sdl.Texture
+}
+
+func (c *blurCache) flush() {
+	for k, t := range c.textures {
+		t.Destroy()
+		delete(c.textures, k)
+	}
+}
+
+func (c *blurCache) clear() {
+	c.flush()
+	if c.source != nil {
+		c.source.Free()
+		c.source = nil
+	}
+}
+
+// drawPanel draws an overlay panel: a dark rounded rect of the given alpha,
+// or half as dark over a blurred copy of the background when hudBlur is on.
+func (g *Game) drawPanel(rect sdl.Rect, alpha uint8) {
+	if g.hudBlur {
+		if t, err := g.frostedBackdrop(rect); err != nil {
+			slog.Warn("could not blur panel background, turning hudBlur off", "err", err)
+			g.hudBlur = false
+		} else {
+			g.renderer.Copy(t, nil, &rect)
+			alpha /= 2
+		}
+	}
+	drawRoundedRect(g.renderer, rect, g.cfg.PanelRadius, sdl.Color{A: alpha})
+}
+
+func (g *Game) frostedBackdrop(rect sdl.Rect) (*sdl.Texture, error) {
+	c := g.blur
+	if c.background != g.background {
+		c.clear()
+		c.background = g.background
+	}
+	if color := (sdl.Color{R: g.bgColor.R, G: g.bgColor.G, B: g.bgColor.B, A: 255}); color != c.color {
+		c.flush()
+		c.color = color
+	}
+	key := blurKey{rect: rect, radius: g.cfg.PanelRadius}
+	if t, ok := c.textures[key]; ok {
+		return t, nil
+	}
+
+	if c.source == nil {
+		source, err := g.backgroundSurface()
+		if err != nil {
+			return nil, err
+		}
+		c.source = source
+	}
+	small, err := sdl.CreateRGBSurfaceWithFormat(0, max(rect.W/hudBlurScale, 1), max(rect.H/hudBlurScale, 1), 32, sdl.PIXELFORMAT_ARGB8888)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating blur surface: %v", err)
+	}
+	defer small.Free()
+	small.FillRect(nil, sdl.MapRGBA(small.Format, c.color.R, c.color.G, c.color.B, 255))
+	if err := c.source.BlitScaled(&rect, small, nil); err != nil {
+		return nil, fmt.Errorf("Error scaling blur surface: %v", err)
+	}
+
+	w, h, pitch := int(small.W), int(small.H), int(small.Pitch)
+	pix := small.Pixels()
+	for i := 0; i < hudBlurPasses; i++ {
+		boxBlur(pix, w, h, pitch, hudBlurRadius)
+	}
+	maskCorners(pix, w, h, pitch, g.cfg.PanelRadius/hudBlurScale)
+
+	sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "linear")
+	t, err := g.renderer.CreateTextureFromSurface(small)
+	sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "")
+	if err != nil {
+		return nil, fmt.Errorf("Error creating blur texture: %v", err)
+	}
+	t.SetBlendMode(sdl.BLENDMODE_BLEND)
+	c.textures[key] = t
+	return t, nil
+}
+
+// backgroundSurface draws the background image into a transparent
+// window-sized surface the way renderBackground draws it with no camera
+// offset.
+func (g *Game) backgroundSurface() (*sdl.Surface, error) {
+	src, err := g.assets.OpenSurface(g.backgroundPath())
+	if err != nil {
+		return nil, fmt.Errorf("Error loading background image: %v", err)
+	}
+	defer src.Free()
+
+	dst, err := sdl.CreateRGBSurfaceWithFormat(0, g.width, g.height, 32, sdl.PIXELFORMAT_ARGB8888)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating background surface: %v", err)
+	}
+	if g.cfg.BackgroundTile == "" {
+		err = src.BlitScaled(nil, dst, &sdl.Rect{W: g.width, H: g.height})
+	} else {
+		for y := int32(0); y < g.height && err == nil; y += src.H {
+			for x := int32(0); x < g.width && err == nil; x += src.W {
+				err = src.Blit(nil, dst, &sdl.Rect{X: x, Y: y})
+			}
+		}
+	}
+	if err != nil {
+		dst.Free()
+		return nil, fmt.Errorf("Error drawing background surface: %v", err)
+	}
+	return dst, nil
+}
+
+// boxBlur blurs 32-bit pixels in place with a horizontal then a vertical box
+// filter of the given radius. Repeated passes approach a gaussian blur.
+func boxBlur(pix []byte, w, h, pitch, radius int) {
+	tmp := make([]byte, len(pix))
+	blur1D(pix, tmp, w, h, 4, pitch, radius)
+	blur1D(tmp, pix, h, w, pitch, 4, radius)
+}
+
+// blur1D averages each pixel with its neighbors within radius along lines
+// of n pixels, where along is the byte step between neighbors and across
+// the step between lines. Pixels past the ends repeat the edge pixel.
+func blur1D(src, dst []byte, n, lines, along, across, radius int) {
+	size := 2*radius + 1
+	at := func(line, i int) int {
+		return line*across + max(0, min(i, n-1))*along
+	}
+	for line := 0; line < lines; line++ {
+		for ch := 0; ch < 4; ch++ {
+			sum := 0
+			for i := -radius; i <= radius; i++ {
+				sum += int(src[at(line, i)+ch])
+			}
+			for i := 0; i < n; i++ {
+				dst[at(line, i)+ch] = byte(sum / size)
+				sum += int(src[at(line, i+radius+1)+ch]) - int(src[at(line, i-radius)+ch])
+			}
+		}
+	}
+}
+
+// maskCorners clears the pixels outside a rounded rect of the given corner
+// radius, matching the shape drawRoundedRect gives the panel.
+func maskCorners(pix []byte, w, h, pitch int, radius int32) {
+	r := int(max(0, min(radius, int32(w/2), int32(h/2))))
+	if r == 0 {
+		return
+	}
+	spans := cornerSpans(int32(r))
+	for i := 0; i < r; i++ {
+		inset := r - int(spans[r-i])
+		for _, y := range []int{i, h - 1 - i} {
+			row := pix[y*pitch:]
+			for x := 0; x < inset; x++ {
+				clear(row[x*4 : x*4+4])
+				clear(row[(w-1-x)*4 : (w-1-x)*4+4])
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Bundle is a zip archive of assets, for shipping the game as the binary
+// and one data file. Asset paths are looked up in it as they are written in
+// the config, relative to the root of the archive.
+type Bundle struct {
+	path  string
+	zip   *zip.ReadCloser
+	files map[string]*zip.File
+}
+
+// OpenBundle opens the zip archive at path. It has to be closed after
+// everything loaded from it was freed.
+func OpenBundle(path string) (*Bundle, error) {
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	b := &Bundle{path: path, zip: z, files: make(map[string]*zip.File)}
+	for _, f := range z.File {
+		if !f.FileInfo().IsDir() {
+			b.files[f.Name] = f
+		}
+	}
+	return b, nil
+}
+
+// Read returns the contents of the file at rel, and whether the archive
+// has it.
+func (b *Bundle) Read(rel string) ([]byte, bool, error) {
+	f, ok := b.files[path.Clean(filepath.ToSlash(rel))]
+	if !ok {
+		return nil, false, nil
+	}
+	r, err := f.Open()
+	if err != nil {
+		return nil, true, fmt.Errorf("opening %s in %s: %v", rel, b.path, err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, true, fmt.Errorf("reading %s in %s: %v", rel, b.path, err)
+	}
+	return data, true, nil
+}
+
+func (b *Bundle) Close() error {
+	return b.zip.Close()
+}
+
+// readAsset reads the asset at rel from the bundle or, when there is none
+// or it doesn't have the file, from the filesystem.
+func (m *AssetManager) readAsset(rel string) ([]byte, error) {
+	if m.bundle != nil {
+		if data, ok, err := m.bundle.Read(rel); ok {
+			return data, err
+		}
+	}
+	return os.ReadFile(rel)
+}
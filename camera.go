@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Camera is the world position shown at the top-left corner of a viewport.
+type Camera struct {
+	Pos Vec2
+}
+
+func (c Camera) ToScreen(p Vec2) Vec2 { return p.Sub(c.Pos) }
+func (c Camera) ToWorld(p Vec2) Vec2  { return p.Add(c.Pos) }
+
+// Apply moves a world-space rect into the camera's screen space.
+func (c Camera) Apply(r sdl.FRect) sdl.FRect {
+	r.X -= float32(c.Pos.X)
+	r.Y -= float32(c.Pos.Y)
+	return r
+}
+
+// viewport is an area of the window showing the scene through a camera.
+type viewport struct {
+	rect   sdl.Rect
+	camera Camera
+}
+
+// viewports lays out the window: one full-window view normally, or two side
+// by side in split screen, the left following the player and the right
+// fixed on the middle of the scene. With the free camera on, the first view
+// shows what it sees instead.
+func (g *Game) viewports() []viewport {
+	vs := g.layoutViewports()
+	if g.freeCamera {
+		vs[0].camera = g.freeCam
+	}
+	return vs
+}
+
+func (g *Game) layoutViewports() []viewport {
+	if !g.splitScreen {
+		return []viewport{{rect: sdl.Rect{W: g.width, H: g.height}}}
+	}
+	half := g.width / 2
+	left := sdl.Rect{W: half, H: g.height}
+	right := sdl.Rect{X: half, W: g.width - half, H: g.height}
+
+	c := g.player.box.Center()
+	follow := Camera{Pos: Vec2{
+		X: clampf(c.X-float64(left.W)/2, 0, float64(g.width-left.W)),
+		Y: clampf(c.Y-float64(left.H)/2, 0, float64(g.height-left.H)),
+	}}
+	fixed := Camera{Pos: Vec2{X: float64(g.width-right.W) / 2}}
+	return []viewport{{rect: left, camera: follow}, {rect: right, camera: fixed}}
+}
+
+// toggleFreeCamera switches the movement input between moving the sprite
+// and panning the first view's camera around the scene. The camera starts
+// from what the view showed, and turning it off hands the view back.
+func (g *Game) toggleFreeCamera() {
+	if !g.freeCamera {
+		g.freeCam = g.viewports()[0].camera
+	}
+	g.freeCamera = !g.freeCamera
+	slog.Debug("free camera", "on", g.freeCamera)
+}
+
+// panCamera moves the free camera as fast as the sprite would move. The
+// camera is clamped to the scene: its position, the scene point at the
+// view's top left corner, stays between 0 and the scene's size on each axis,
+// so a view as big as the scene can still be panned across it.
+func (g *Game) panCamera(dt float64) {
+	dx, dy := g.movement(float64(g.spriteVelocity) * dt)
+	c := &g.freeCam.Pos
+	c.X = clampf(c.X+dx, 0, float64(g.width))
+	c.Y = clampf(c.Y+dy, 0, float64(g.height))
+}
+
+func (g *Game) cameraLine() string {
+	if !g.freeCamera {
+		return "Camera: sprite"
+	}
+	return fmt.Sprintf("Camera: free at %.0f,%.0f", g.freeCam.Pos.X, g.freeCam.Pos.Y)
+}
+
+// renderDivider draws the line between the split screen halves.
+func (g *Game) renderDivider() {
+	r, gr, b, a, _ := g.renderer.GetDrawColor()
+	defer g.renderer.SetDrawColor(r, gr, b, a)
+	g.renderer.SetDrawColor(0, 0, 0, 255)
+	g.renderer.FillRect(&sdl.Rect{X: g.width/2 - 1, W: 2, H: g.height})
+}
+
+// screenToWorld maps a window position to the world position under it in
+// whichever viewport contains it.
+func (g *Game) screenToWorld(x, y int32) (Vec2, bool) {
+	for _, v := range g.viewports() {
+		p := sdl.Point{X: x, Y: y}
+		if p.InRect(&v.rect) {
+			return v.camera.ToWorld(Vec2{X: float64(x - v.rect.X), Y: float64(y - v.rect.Y)}), true
+		}
+	}
+	return Vec2{}, false
+}
+
+// worldToScreen maps a world-space rect to where the first viewport draws
+// it in the window.
+func (g *Game) worldToScreen(r AABB) sdl.Rect {
+	v := g.viewports()[0]
+	p := v.camera.ToScreen(Vec2{X: r.X, Y: r.Y})
+	return AABB{X: p.X + float64(v.rect.X), Y: p.Y + float64(v.rect.Y), W: r.W, H: r.H}.Rect()
+}
+
+func clampf(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(v, hi))
+}
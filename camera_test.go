@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// hold presses key, or lets go of it, as the keyboard would.
+func hold(g *Game, key sdl.Scancode, down bool) {
+	e := &sdl.KeyboardEvent{Type: sdl.KEYUP, Keysym: sdl.Keysym{Scancode: key}}
+	if down {
+		e.Type = sdl.KEYDOWN
+	}
+	g.input.Handle(e)
+}
+
+func TestPanCameraFullWindow(t *testing.T) {
+	g := &Game{cfg: DefaultConfig(), input: NewInputManager(), width: 800, height: 600, spriteVelocity: 10}
+	if v := g.viewports()[0].rect; v.W != g.width || v.H != g.height {
+		t.Fatalf("the view is %+v, want the whole window", v)
+	}
+	g.toggleFreeCamera()
+
+	hold(g, sdl.SCANCODE_RIGHT, true)
+	hold(g, sdl.SCANCODE_DOWN, true)
+	for i := 0; i < 5; i++ {
+		g.panCamera(1)
+	}
+	if got, want := g.freeCam.Pos, (Vec2{X: 50, Y: 50}); got != want {
+		t.Errorf("after panning right and down the camera is at %+v, want %+v", got, want)
+	}
+	for i := 0; i < 100; i++ {
+		g.panCamera(1)
+	}
+	if got, want := g.freeCam.Pos, (Vec2{X: 800, Y: 600}); got != want {
+		t.Errorf("panning right and down stopped at %+v, want the scene's far corner %+v", got, want)
+	}
+	hold(g, sdl.SCANCODE_RIGHT, false)
+	hold(g, sdl.SCANCODE_DOWN, false)
+
+	hold(g, sdl.SCANCODE_LEFT, true)
+	hold(g, sdl.SCANCODE_UP, true)
+	for i := 0; i < 100; i++ {
+		g.panCamera(1)
+	}
+	if got := g.freeCam.Pos; got != (Vec2{}) {
+		t.Errorf("panning left and up stopped at %+v, want the scene's top left corner", got)
+	}
+
+	if got := g.viewports()[0].camera; got != g.freeCam {
+		t.Errorf("the view shows %+v, want the free camera %+v", got, g.freeCam)
+	}
+	g.toggleFreeCamera()
+	if got := g.viewports()[0].camera; got != (Camera{}) {
+		t.Errorf("after free-look the view shows %+v, want the fixed full-window camera", got)
+	}
+}
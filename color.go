@@ -0,0 +1,43 @@
+package main
+
+import "math"
+
+// hsvToRGB converts a hue in degrees and saturation/value in [0, 1] to RGB.
+func hsvToRGB(h, s, v float64) (r, g, b uint8) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+
+	var rf, gf, bf float64
+	switch {
+	case h < 60:
+		rf, gf, bf = c, x, 0
+	case h < 120:
+		rf, gf, bf = x, c, 0
+	case h < 180:
+		rf, gf, bf = 0, c, x
+	case h < 240:
+		rf, gf, bf = 0, x, c
+	case h < 300:
+		rf, gf, bf = x, 0, c
+	default:
+		rf, gf, bf = c, 0, x
+	}
+	return uint8(math.Round((rf + m) * 255)), uint8(math.Round((gf + m) * 255)), uint8(math.Round((bf + m) * 255))
+}
+
+// beatBrightness is how bright the background is at ticks milliseconds into
+// a beat of bpm beats per minute: full on the beat, easing down to 60%
+// just before the next one.
+func beatBrightness(ticks uint32, bpm int) float64 {
+	_, phase := math.Modf(float64(ticks) * float64(bpm) / 60000)
+	return 0.6 + 0.4*(1-phase)*(1-phase)
+}
+
+func scaleColor(r, g, b uint8, f float64) (uint8, uint8, uint8) {
+	return uint8(float64(r) * f), uint8(float64(g) * f), uint8(float64(b) * f)
+}
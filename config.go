@@ -0,0 +1,757 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/veandco/go-sdl2/mix"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const configPath = "config.json"
+
+// slowestSpeed is the least any speed setting may be, in pixels per second.
+// Speeds used to be given in pixels per frame at 50 fps, which puts all of
+// an older config's well below it.
+const slowestSpeed = 25
+
+// Edge behaviors for the sprite when it reaches a window border.
+const (
+	edgeClamp  = "clamp"
+	edgeBounce = "bounce"
+	edgeWrap   = "wrap"
+)
+
+// Config holds the tunable settings of the game. Any field missing from the
+// config file keeps its default value.
+type Config struct {
+	LeftBehavior   string `json:"leftBehavior"`
+	RightBehavior  string `json:"rightBehavior"`
+	TopBehavior    string `json:"topBehavior"`
+	BottomBehavior string `json:"bottomBehavior"`
+	LogLevel       string `json:"logLevel"`
+
+	// WindowWidth and WindowHeight are the size the window opens at. It can
+	// be resized after that.
+	WindowWidth  int32 `json:"windowWidth"`
+	WindowHeight int32 `json:"windowHeight"`
+
+	// RememberWindow saves the window's position and size on quit and opens
+	// it there again on the next run, instead of centered at WindowWidth by
+	// WindowHeight.
+	RememberWindow bool `json:"rememberWindow"`
+
+	// WindowFlags are extra window creation flags, e.g. "resizable,highdpi".
+	WindowFlags string `json:"windowFlags"`
+
+	// AlwaysOnTop keeps the window above all others. It needs SDL 2.0.16.
+	AlwaysOnTop bool `json:"alwaysOnTop"`
+
+	// Background is the image stretched across the window behind the scene.
+	// BackgroundTile, when set, is an image repeated across the window in
+	// its place.
+	Background     string `json:"background"`
+	BackgroundTile string `json:"backgroundTile"`
+
+	// SpriteImage is the image the sprites are drawn with.
+	SpriteImage ImageConfig `json:"spriteImage"`
+
+	// SpriteFrames splits SpriteImage into that many frames side by side,
+	// played in a loop at SpriteFPS frames a second.
+	SpriteFrames int     `json:"spriteFrames"`
+	SpriteFPS    float64 `json:"spriteFPS"`
+
+	// TintAnimation cycles the sprite's color mod through the hue wheel at
+	// TintSpeed degrees per second.
+	TintAnimation bool    `json:"tintAnimation"`
+	TintSpeed     float64 `json:"tintSpeed"`
+
+	// SpriteJitter offsets where the sprite is drawn by up to JitterAmplitude
+	// pixels each frame without moving it.
+	SpriteJitter    bool    `json:"spriteJitter"`
+	JitterAmplitude float64 `json:"jitterAmplitude"`
+
+	// Seed seeds the game's random number generator. Zero picks a seed from
+	// the current time.
+	Seed int64 `json:"seed"`
+
+	// ColorCycling changes the background color every second.
+	ColorCycling bool `json:"colorCycling"`
+
+	// BPMPulse pulses the background brightness at BPM beats per minute.
+	BPMPulse bool `json:"bpmPulse"`
+	BPM      int  `json:"bpm"`
+
+	// Afterimage leaves fading ghosts of moving things behind them by
+	// fading the last frame by AfterimageFade, from 1 to 255, instead of
+	// clearing it. Higher values fade faster.
+	Afterimage     bool  `json:"afterimage"`
+	AfterimageFade uint8 `json:"afterimageFade"`
+
+	// TextTrail draws the title again at its last TextTrailLength
+	// positions, the newest at TextTrailAlpha and older ones fading out.
+	TextTrail       bool  `json:"textTrail"`
+	TextTrailLength int   `json:"textTrailLength"`
+	TextTrailAlpha  uint8 `json:"textTrailAlpha"`
+
+	// BounceParticles is how many particles burst from where the title
+	// bounces off a wall, 0 for none. At most MaxParticles are alive at
+	// once, the oldest making way for new ones.
+	BounceParticles int `json:"bounceParticles"`
+	MaxParticles    int `json:"maxParticles"`
+
+	// SquashAmount is how much the title and sprite squash along the axis
+	// of a bounce, and stretch along the other, as a fraction of their size
+	// from 0 (not at all) to below 1. They spring back over SquashSeconds.
+	SquashAmount  float64 `json:"squashAmount"`
+	SquashSeconds float64 `json:"squashSeconds"`
+
+	// ReducedMotion turns off pulsing effects and squash and stretch,
+	// overriding their settings.
+	ReducedMotion bool `json:"reducedMotion"`
+
+	// DebugWindow opens a second window showing the debug stats.
+	DebugWindow bool `json:"debugWindow"`
+
+	// ShowInputs shows recently pressed keys along the bottom of the window,
+	// at most InputDisplayMax of them, each fading out over
+	// InputDisplaySeconds.
+	ShowInputs          bool    `json:"showInputs"`
+	InputDisplaySeconds float64 `json:"inputDisplaySeconds"`
+	InputDisplayMax     int     `json:"inputDisplayMax"`
+
+	// SplitScreen draws the scene twice side by side, one half following
+	// the player and the other fixed on the middle of the window.
+	SplitScreen bool `json:"splitScreen"`
+
+	// HudBlur puts a blurred copy of the background behind the overlay
+	// panels.
+	HudBlur bool `json:"hudBlur"`
+
+	// PanelRadius rounds the corners of the menu and overlay panels, in
+	// pixels. Zero draws square panels.
+	PanelRadius int32 `json:"panelRadius"`
+
+	// IntroAnimation slides the sprite in and fades the title in at start.
+	IntroAnimation bool `json:"introAnimation"`
+
+	// TitleScreen starts the game on a title screen that Enter leaves.
+	// Escape then goes back to it rather than quitting, if it quits at all.
+	TitleScreen bool `json:"titleScreen"`
+
+	// PauseOnMenu freezes gameplay and music while the options menu is open.
+	PauseOnMenu bool `json:"pauseOnMenu"`
+
+	// Velocities are in pixels per second; movement is scaled to the actual
+	// frame time.
+	SpriteVelocity int       `json:"spriteVelocity"`
+	TextVelocity   int       `json:"textVelocity"`
+	TextColor      sdl.Color `json:"textColor"`
+
+	// MinTextSpeed and MaxTextSpeed bound the title's speed along each axis
+	// after every bounce, so it neither stops against a wall nor gets too
+	// fast to follow.
+	MinTextSpeed int `json:"minTextSpeed"`
+	MaxTextSpeed int `json:"maxTextSpeed"`
+
+	// TextMode is how the title moves: "bounce" off the edges at
+	// TextVelocity, "marquee" to scroll right to left at MarqueeSpeed,
+	// wrapping around, or "static".
+	TextMode     string  `json:"textMode"`
+	MarqueeSpeed float64 `json:"marqueeSpeed"`
+
+	// StickMode is how the left controller stick moves the sprite:
+	// "analog" at a speed proportional to how far it is pushed or
+	// "digital" at full speed like the arrow keys. Each axis is ignored
+	// while pushed less than StickThreshold, from 0 to 1, of its travel.
+	StickMode      string  `json:"stickMode"`
+	StickThreshold float64 `json:"stickThreshold"`
+
+	// MaxDeltaTime caps the frame time, in seconds, that movement is scaled
+	// by.
+	MaxDeltaTime float64 `json:"maxDeltaTime"`
+
+	// FixedTimestep, when positive, updates the game in steps of that many
+	// seconds, as many as the frame time adds up to, rather than once per
+	// frame by the frame time. MaxUpdatesPerFrame caps how many run in one
+	// frame; time past that is dropped.
+	FixedTimestep      float64 `json:"fixedTimestep"`
+	MaxUpdatesPerFrame int     `json:"maxUpdatesPerFrame"`
+
+	// TargetFPS is how many frames are rendered per second. With
+	// DecoupleInput events are polled and the game updated between frames
+	// too, for lower input latency, rather than once per frame.
+	TargetFPS     int  `json:"targetFPS"`
+	DecoupleInput bool `json:"decoupleInput"`
+
+	// The title is drawn over a drop shadow in ShadowColor, whose alpha is
+	// the shadow's intensity (0 turns it off), offset by ShadowOffsetX/Y
+	// and blurred over ShadowBlur pixels.
+	ShadowColor   sdl.Color `json:"shadowColor"`
+	ShadowOffsetX int32     `json:"shadowOffsetX"`
+	ShadowOffsetY int32     `json:"shadowOffsetY"`
+	ShadowBlur    int32     `json:"shadowBlur"`
+
+	// Volume is the master volume, 0-128.
+	Volume int `json:"volume"`
+
+	// PredictiveBounceAudio starts the bounce sound one frame before the text
+	// reaches a wall to make up for mixer latency.
+	PredictiveBounceAudio bool `json:"predictiveBounceAudio"`
+
+	// SpeedReactiveAudio plays faster bounces louder, and higher when there
+	// are pitch variants.
+	SpeedReactiveAudio bool `json:"speedReactiveAudio"`
+
+	// LeftBounceSound and the others say which walls the title plays the
+	// bounce sound on.
+	LeftBounceSound   bool `json:"leftBounceSound"`
+	RightBounceSound  bool `json:"rightBounceSound"`
+	TopBounceSound    bool `json:"topBounceSound"`
+	BottomBounceSound bool `json:"bottomBounceSound"`
+
+	// QuitKey is the SDL name of a key that quits, e.g. "Q". Escape quits
+	// too unless EscapeQuits is false, in which case it closes menus and
+	// overlays instead.
+	QuitKey     string `json:"quitKey"`
+	EscapeQuits bool   `json:"escapeQuits"`
+
+	// ResetKey is the SDL name of the key that puts the settings back to
+	// those of the config file, "" for none.
+	ResetKey string `json:"resetKey"`
+
+	// ControllerBindings maps actions to the SDL names of the controller
+	// buttons that do them, e.g. "a" or "start". An empty name unbinds one.
+	ControllerBindings map[string]string `json:"controllerBindings"`
+
+	// ConfirmQuit asks before the quit keys quit, giving up on the
+	// question after QuitConfirmTimeoutSeconds, or never if 0.
+	ConfirmQuit               bool `json:"confirmQuit"`
+	QuitConfirmTimeoutSeconds int  `json:"quitConfirmTimeoutSeconds"`
+
+	// Sounds maps game events to the sound they play and Music is the
+	// playlist, played in order and from the top again after the last
+	// track. Loops counts repeats after the first play; -1 repeats forever.
+	Sounds map[string]SoundConfig `json:"sounds"`
+	Music  []MusicTrack           `json:"music"`
+
+	// PacingMode is how the loop keeps to TargetFPS: "vsync", "delay",
+	// "spin" or "uncapped", see pacing.go. At startup VSyncProbeFrames frames
+	// are timed to check whether vsync is really in effect; 0 skips the
+	// check.
+	PacingMode       string `json:"pacingMode"`
+	VSyncProbeFrames int    `json:"vsyncProbeFrames"`
+
+	// Audio is the sample rate, format, channels and chunk size the audio
+	// device is opened with. Settings the device rejects fall back to the
+	// defaults.
+	Audio AudioConfig `json:"audio"`
+
+	// PitchVariation plays the bounce sound at one of PitchVariants
+	// slightly different pitches each time, chosen at random.
+	PitchVariation bool `json:"pitchVariation"`
+	PitchVariants  int  `json:"pitchVariants"`
+
+	// SoundChannels is how many sounds can play at once. When all are
+	// busy SoundPolicy decides what happens: "drop" skips the new sound and
+	// "steal" stops the oldest one to make room.
+	SoundChannels int    `json:"soundChannels"`
+	SoundPolicy   string `json:"soundPolicy"`
+
+	// StereoPanning pans the bounce sound to where across the window the
+	// title or sprite bounced.
+	StereoPanning bool `json:"stereoPanning"`
+
+	// Icons are the window icon at different sizes. The one closest to
+	// what the platform displays is used, falling back to the others if it
+	// can't be loaded.
+	Icons []IconConfig `json:"icons"`
+
+	// Fonts are opened at startup and looked up by name. A font named "ui"
+	// is required since it is the fallback for any name not listed.
+	Fonts []FontConfig `json:"fonts"`
+
+	// DPIScaleFonts scales font sizes by the DPI of the display the window
+	// is on, relative to 96, reopening them when it moves to another one.
+	DPIScaleFonts bool `json:"dpiScaleFonts"`
+
+	// OverlayOrder overrides the priorities the overlays are stacked by,
+	// e.g. {"console": 5}. Overlays with higher priorities are drawn over
+	// those with lower ones.
+	OverlayOrder map[string]int `json:"overlayOrder"`
+
+	// FocusMode starts the game with the overlays hidden, as F2 toggles,
+	// except for those named in FocusModeKeeps. By default those are the
+	// ones waiting for input, which would be lost otherwise.
+	FocusMode      bool     `json:"focusMode"`
+	FocusModeKeeps []string `json:"focusModeKeeps"`
+
+	// IdleDimSeconds, when positive, dims the screen after that many
+	// seconds without input, fading down to IdleDimLevel brightness, from
+	// 0 to 1. Any input brings it back.
+	IdleDimSeconds float64 `json:"idleDimSeconds"`
+	IdleDimLevel   float64 `json:"idleDimLevel"`
+
+	// ShowCrosshair draws a cross at the mouse in place of the system
+	// cursor, CrosshairSize pixels out from it each way in CrosshairColor.
+	ShowCrosshair  bool      `json:"showCrosshair"`
+	CrosshairColor sdl.Color `json:"crosshairColor"`
+	CrosshairSize  int32     `json:"crosshairSize"`
+
+	// SpinnerSize is the radius in pixels of the spinner shown while the
+	// assets load, 0 for none, and SpinnerColor its color.
+	SpinnerSize  int32     `json:"spinnerSize"`
+	SpinnerColor sdl.Color `json:"spinnerColor"`
+
+	// MaxSprites is how many sprites there can be, counting the player.
+	// Spawning more removes the oldest spawned ones first.
+	MaxSprites int `json:"maxSprites"`
+
+	// SpriteLifetime is how many seconds spawned sprites live before they
+	// are removed, 0 for forever. They fade out over the last SpriteFadeOut
+	// fraction of it, from 0 (not at all) to 1 (all of it).
+	SpriteLifetime float64 `json:"spriteLifetime"`
+	SpriteFadeOut  float64 `json:"spriteFadeOut"`
+
+	// OverlayEffect darkens the scene in a pattern for a retro look:
+	// "scanlines", a "vignette", both as "crt", or "none".
+	OverlayEffect string `json:"overlayEffect"`
+
+	// SpawnPattern is how the SpawnCount sprites spawned at once are laid
+	// out: "random" anywhere in the window, or around the player in a
+	// "grid", a "circle" or a random "cluster".
+	SpawnPattern string `json:"spawnPattern"`
+	SpawnCount   int    `json:"spawnCount"`
+
+	// Flocking makes spawned sprites follow the boids rules around the
+	// player. Neighbors are the sprites within FlockRadius pixels, each rule
+	// is scaled by its weight and FlockSpeed is the top speed in pixels per
+	// second.
+	Flocking         bool    `json:"flocking"`
+	FlockRadius      float64 `json:"flockRadius"`
+	FlockSpeed       float64 `json:"flockSpeed"`
+	SeparationWeight float64 `json:"separationWeight"`
+	AlignmentWeight  float64 `json:"alignmentWeight"`
+	CohesionWeight   float64 `json:"cohesionWeight"`
+	LeaderWeight     float64 `json:"leaderWeight"`
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		LeftBehavior:        edgeClamp,
+		RightBehavior:       edgeClamp,
+		TopBehavior:         edgeClamp,
+		BottomBehavior:      edgeClamp,
+		LeftBounceSound:     true,
+		RightBounceSound:    true,
+		TopBounceSound:      true,
+		BottomBounceSound:   true,
+		WindowWidth:         800,
+		WindowHeight:        600,
+		RememberWindow:      true,
+		LogLevel:            "info",
+		TintSpeed:           60,
+		ColorCycling:        true,
+		BPM:                 120,
+		AfterimageFade:      64,
+		PanelRadius:         8,
+		InputDisplaySeconds: 2,
+		InputDisplayMax:     8,
+		JitterAmplitude:     1.5,
+		SpriteVelocity:      500,
+		TextVelocity:        100,
+		MinTextSpeed:        50,
+		MaxTextSpeed:        1000,
+		TextMode:            textBounce,
+		MarqueeSpeed:        150,
+		TextColor:           sdl.Color{R: 255, G: 255, B: 255, A: 255},
+		MaxDeltaTime:        0.1,
+		MaxUpdatesPerFrame:  5,
+		StickMode:           stickAnalog,
+		StickThreshold:      0.25,
+		TargetFPS:           60,
+		ShadowColor:         sdl.Color{A: 160},
+		ShadowOffsetX:       3,
+		ShadowOffsetY:       3,
+		ShadowBlur:          2,
+		Volume:              mix.MAX_VOLUME,
+		QuitKey:             "Escape",
+		ResetKey:            "F6",
+		EscapeQuits:         true,
+		Sounds: map[string]SoundConfig{
+			soundAction: {Path: "sounds/Go.ogg"},
+			soundBounce: {Path: "sounds/SDL.ogg"},
+		},
+		Music: []MusicTrack{
+			{Path: "music/freesoftwaresong-8bit.ogg", Loops: -1},
+		},
+		Background:   "images/background.png",
+		SpriteImage:  ImageConfig{Path: "images/Go-logo.png"},
+		SpriteFrames: 1,
+		SpriteFPS:    10,
+		ControllerBindings: map[string]string{
+			bindAction: "a",
+			bindMusic:  "start",
+			bindBack:   "b",
+		},
+		QuitConfirmTimeoutSeconds: 5,
+		SoundChannels:             16,
+		StereoPanning:             true,
+		SoundPolicy:               soundPolicyDrop,
+		Audio:                     defaultAudioConfig(),
+		PitchVariants:             5,
+		PacingMode:                pacingVSync,
+		VSyncProbeFrames:          10,
+		MaxSprites:                300,
+		SpriteFadeOut:             0.25,
+		SpawnPattern:              spawnRandom,
+		OverlayEffect:             effectNone,
+		SpawnCount:                1,
+		IdleDimLevel:              0.3,
+		TextTrailLength:           8,
+		TextTrailAlpha:            96,
+		BounceParticles:           12,
+		MaxParticles:              256,
+		SquashAmount:              0.2,
+		SquashSeconds:             0.3,
+		TitleScreen:               true,
+		CrosshairColor:            sdl.Color{R: 255, G: 255, B: 255, A: 255},
+		CrosshairSize:             8,
+		SpinnerSize:               24,
+		SpinnerColor:              sdl.Color{R: 255, G: 255, B: 255, A: 255},
+		FocusModeKeeps:            []string{overlayMenu, overlayConsole, overlayQuitConfirm},
+		Icons: []IconConfig{
+			{Path: "images/Go-logo.png", Size: 128},
+		},
+		Fonts: []FontConfig{
+			{Name: fontTitle, Path: "fonts/freesansbold.ttf", Size: 80},
+			{Name: fontUI, Path: "fonts/freesansbold.ttf", Size: 14},
+			{Name: fontConsole, Path: "fonts/freesansbold.ttf", Size: 13},
+		},
+		FlockRadius:      80,
+		FlockSpeed:       200,
+		SeparationWeight: 1.5,
+		AlignmentWeight:  1,
+		CohesionWeight:   1,
+		LeaderWeight:     0.5,
+	}
+}
+
+// LoadConfig reads the config file at path on top of the defaults. A missing
+// file is not an error, the defaults are returned instead.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DefaultConfig(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading config: %v", err)
+	}
+	return parseConfig(path, data)
+}
+
+// parseConfig decodes data, read from path, on top of the defaults and
+// validates the result.
+func parseConfig(path string, data []byte) (*Config, error) {
+	cfg := DefaultConfig()
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("Error parsing config %s: %v", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("Error in config %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	edges := []struct {
+		name, value string
+	}{
+		{"leftBehavior", c.LeftBehavior},
+		{"rightBehavior", c.RightBehavior},
+		{"topBehavior", c.TopBehavior},
+		{"bottomBehavior", c.BottomBehavior},
+	}
+	for _, e := range edges {
+		switch e.value {
+		case edgeClamp, edgeBounce, edgeWrap:
+		default:
+			return fmt.Errorf("%s must be %q, %q or %q, got %q", e.name, edgeClamp, edgeBounce, edgeWrap, e.value)
+		}
+	}
+	if _, err := parseWindowFlags(c.WindowFlags); err != nil {
+		return fmt.Errorf("windowFlags: %v", err)
+	}
+	// Turning down an older config's per-frame speeds beats running
+	// everything 50 times slower than it used to.
+	speeds := []struct {
+		name  string
+		value float64
+	}{
+		{"spriteVelocity", float64(c.SpriteVelocity)},
+		{"textVelocity", float64(c.TextVelocity)},
+		{"marqueeSpeed", c.MarqueeSpeed},
+		{"flockSpeed", c.FlockSpeed},
+	}
+	for _, s := range speeds {
+		if s.value < slowestSpeed {
+			return fmt.Errorf("%s must be at least %d pixels per second, got %v (speeds used to be per frame at 50 fps: multiply older ones by 50)", s.name, slowestSpeed, s.value)
+		}
+	}
+	if c.MinTextSpeed < 1 || c.MaxTextSpeed < c.MinTextSpeed {
+		return fmt.Errorf("minTextSpeed must be at least 1 and maxTextSpeed at least minTextSpeed, got %d and %d", c.MinTextSpeed, c.MaxTextSpeed)
+	}
+	if c.TextVelocity < c.MinTextSpeed || c.TextVelocity > c.MaxTextSpeed {
+		return fmt.Errorf("textVelocity must be between minTextSpeed and maxTextSpeed (%d to %d pixels per second), got %d", c.MinTextSpeed, c.MaxTextSpeed, c.TextVelocity)
+	}
+	switch c.TextMode {
+	case textBounce, textMarquee, textStatic:
+	default:
+		return fmt.Errorf("textMode must be %q, %q or %q, got %q", textBounce, textMarquee, textStatic, c.TextMode)
+	}
+	if err := validateControllerBindings(c.ControllerBindings); err != nil {
+		return err
+	}
+	if c.QuitConfirmTimeoutSeconds < 0 {
+		return fmt.Errorf("quitConfirmTimeoutSeconds must be 0 (no timeout) or more, got %d", c.QuitConfirmTimeoutSeconds)
+	}
+	if c.StickMode != stickAnalog && c.StickMode != stickDigital {
+		return fmt.Errorf("stickMode must be %q or %q, got %q", stickAnalog, stickDigital, c.StickMode)
+	}
+	if c.StickThreshold < 0 || c.StickThreshold >= 1 {
+		return fmt.Errorf("stickThreshold must be at least 0 and below 1, got %v", c.StickThreshold)
+	}
+	if c.TargetFPS < 1 || c.TargetFPS > 1000 {
+		return fmt.Errorf("targetFPS must be between 1 and 1000, got %d", c.TargetFPS)
+	}
+	if err := validatePacingMode(c.PacingMode); err != nil {
+		return err
+	}
+	if c.VSyncProbeFrames < 0 || c.VSyncProbeFrames > 120 {
+		return fmt.Errorf("vsyncProbeFrames must be between 0 and 120, got %d", c.VSyncProbeFrames)
+	}
+	if c.MaxDeltaTime <= 0 {
+		return fmt.Errorf("maxDeltaTime must be positive, got %v", c.MaxDeltaTime)
+	}
+	if c.FixedTimestep < 0 || c.FixedTimestep > c.MaxDeltaTime {
+		return fmt.Errorf("fixedTimestep must be 0 (update once per frame) or up to maxDeltaTime, got %v", c.FixedTimestep)
+	}
+	if c.MaxUpdatesPerFrame < 1 {
+		return fmt.Errorf("maxUpdatesPerFrame must be at least 1, got %d", c.MaxUpdatesPerFrame)
+	}
+	if c.Volume < 0 || c.Volume > mix.MAX_VOLUME {
+		return fmt.Errorf("volume must be between 0 and %d, got %d", mix.MAX_VOLUME, c.Volume)
+	}
+	if c.JitterAmplitude < 0 {
+		return fmt.Errorf("jitterAmplitude must not be negative, got %v", c.JitterAmplitude)
+	}
+	if c.QuitKey != "" && sdl.GetKeyFromName(c.QuitKey) == sdl.K_UNKNOWN {
+		return fmt.Errorf("quitKey %q is not a known key name", c.QuitKey)
+	}
+	if c.ResetKey != "" && sdl.GetKeyFromName(c.ResetKey) == sdl.K_UNKNOWN {
+		return fmt.Errorf("resetKey %q is not a known key name", c.ResetKey)
+	}
+	if err := validateFonts(c.Fonts); err != nil {
+		return err
+	}
+	if c.SoundChannels < 1 || c.SoundChannels > 256 {
+		return fmt.Errorf("soundChannels must be between 1 and 256, got %d", c.SoundChannels)
+	}
+	if c.SoundPolicy != soundPolicyDrop && c.SoundPolicy != soundPolicySteal {
+		return fmt.Errorf("soundPolicy must be %q or %q, got %q", soundPolicyDrop, soundPolicySteal, c.SoundPolicy)
+	}
+	if c.Background == "" {
+		return fmt.Errorf("background needs a path")
+	}
+	if c.SpriteImage.Path == "" {
+		return fmt.Errorf("spriteImage needs a path")
+	}
+	if err := validateIcons(c.Icons); err != nil {
+		return err
+	}
+	if c.SpriteFrames < 1 {
+		return fmt.Errorf("spriteFrames must be at least 1, got %d", c.SpriteFrames)
+	}
+	if c.SpriteFPS < 0 {
+		return fmt.Errorf("spriteFPS must be 0 (still) or more, got %v", c.SpriteFPS)
+	}
+	if c.PitchVariants < 2 || c.PitchVariants > 16 {
+		return fmt.Errorf("pitchVariants must be between 2 and 16, got %d", c.PitchVariants)
+	}
+	if err := validateAudio(c.Audio); err != nil {
+		return err
+	}
+	if err := validateSounds(c.Sounds); err != nil {
+		return err
+	}
+	if err := validateMusic(c.Music); err != nil {
+		return err
+	}
+	if c.ShadowBlur < 0 || c.ShadowBlur > 8 {
+		return fmt.Errorf("shadowBlur must be between 0 and 8, got %d", c.ShadowBlur)
+	}
+	if c.InputDisplaySeconds <= 0 {
+		return fmt.Errorf("inputDisplaySeconds must be positive, got %v", c.InputDisplaySeconds)
+	}
+	if c.InputDisplayMax <= 0 {
+		return fmt.Errorf("inputDisplayMax must be positive, got %d", c.InputDisplayMax)
+	}
+	if c.BPM <= 0 || c.BPM > 300 {
+		return fmt.Errorf("bpm must be between 1 and 300, got %d", c.BPM)
+	}
+	if c.AfterimageFade == 0 {
+		return fmt.Errorf("afterimageFade must be between 1 and 255, got 0")
+	}
+	if c.TextTrailLength < 1 || c.TextTrailLength > 64 {
+		return fmt.Errorf("textTrailLength must be between 1 and 64, got %d", c.TextTrailLength)
+	}
+	if c.TextTrailAlpha == 0 {
+		return fmt.Errorf("textTrailAlpha must be between 1 and 255, got 0")
+	}
+	if c.BounceParticles < 0 {
+		return fmt.Errorf("bounceParticles must not be negative, got %d", c.BounceParticles)
+	}
+	if c.MaxParticles < 1 {
+		return fmt.Errorf("maxParticles must be at least 1, got %d", c.MaxParticles)
+	}
+	if c.SquashAmount < 0 || c.SquashAmount >= 1 {
+		return fmt.Errorf("squashAmount must be at least 0 and below 1, got %v", c.SquashAmount)
+	}
+	if c.SquashSeconds <= 0 {
+		return fmt.Errorf("squashSeconds must be positive, got %v", c.SquashSeconds)
+	}
+	if c.PanelRadius < 0 {
+		return fmt.Errorf("panelRadius must not be negative, got %d", c.PanelRadius)
+	}
+	if c.TintSpeed <= 0 {
+		return fmt.Errorf("tintSpeed must be positive, got %v", c.TintSpeed)
+	}
+	if err := validateOverlayOrder(c.OverlayOrder); err != nil {
+		return err
+	}
+	if err := validateFocusModeKeeps(c.FocusModeKeeps); err != nil {
+		return err
+	}
+	if c.IdleDimSeconds < 0 {
+		return fmt.Errorf("idleDimSeconds must be 0 (never dim) or more, got %v", c.IdleDimSeconds)
+	}
+	if c.IdleDimLevel < 0 || c.IdleDimLevel > 1 {
+		return fmt.Errorf("idleDimLevel must be between 0 and 1, got %v", c.IdleDimLevel)
+	}
+	if c.WindowWidth < 1 || c.WindowHeight < 1 {
+		return fmt.Errorf("windowWidth and windowHeight must be positive, got %dx%d", c.WindowWidth, c.WindowHeight)
+	}
+	if c.CrosshairSize < 1 {
+		return fmt.Errorf("crosshairSize must be at least 1, got %d", c.CrosshairSize)
+	}
+	if c.SpinnerSize < 0 {
+		return fmt.Errorf("spinnerSize must be 0 (no spinner) or more, got %d", c.SpinnerSize)
+	}
+	if c.MaxSprites < 2 {
+		return fmt.Errorf("maxSprites must be at least 2, got %d", c.MaxSprites)
+	}
+	if c.SpriteLifetime < 0 {
+		return fmt.Errorf("spriteLifetime must not be negative, got %v", c.SpriteLifetime)
+	}
+	if c.SpriteFadeOut < 0 || c.SpriteFadeOut > 1 {
+		return fmt.Errorf("spriteFadeOut must be between 0 and 1, got %v", c.SpriteFadeOut)
+	}
+	if err := validateOverlayEffect(c.OverlayEffect); err != nil {
+		return err
+	}
+	if err := validateSpawnPattern(c.SpawnPattern); err != nil {
+		return err
+	}
+	if c.SpawnCount < 1 {
+		return fmt.Errorf("spawnCount must be at least 1, got %d", c.SpawnCount)
+	}
+	if c.FlockRadius <= 0 {
+		return fmt.Errorf("flockRadius must be positive, got %v", c.FlockRadius)
+	}
+	weights := []struct {
+		name  string
+		value float64
+	}{
+		{"separationWeight", c.SeparationWeight},
+		{"alignmentWeight", c.AlignmentWeight},
+		{"cohesionWeight", c.CohesionWeight},
+		{"leaderWeight", c.LeaderWeight},
+	}
+	for _, w := range weights {
+		if w.value < 0 {
+			return fmt.Errorf("%s must not be negative, got %v", w.name, w.value)
+		}
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		return fmt.Errorf("logLevel must be debug, info, warn or error, got %q", c.LogLevel)
+	}
+	return nil
+}
+
+func (c *Config) logLevel() slog.Level {
+	var level slog.Level
+	level.UnmarshalText([]byte(c.LogLevel))
+	return level
+}
+
+func validateFonts(fonts []FontConfig) error {
+	names := make(map[string]bool, len(fonts))
+	for _, f := range fonts {
+		if f.Name == "" || f.Path == "" {
+			return fmt.Errorf("fonts: every font needs a name and a path")
+		}
+		if f.Size <= 0 {
+			return fmt.Errorf("fonts: size of %q must be positive, got %d", f.Name, f.Size)
+		}
+		if names[f.Name] {
+			return fmt.Errorf("fonts: %q is listed twice", f.Name)
+		}
+		names[f.Name] = true
+	}
+	if !names[fontUI] {
+		return fmt.Errorf("fonts: a font named %q is required", fontUI)
+	}
+	return nil
+}
+
+type configChange struct {
+	name     string
+	old, new any
+}
+
+// configChanges lists the settings that differ between two configs, named
+// by their JSON keys.
+func configChanges(old, new *Config) []configChange {
+	var changes []configChange
+	ov, nv := reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem()
+	for i := 0; i < ov.NumField(); i++ {
+		o, n := ov.Field(i).Interface(), nv.Field(i).Interface()
+		if reflect.DeepEqual(o, n) {
+			continue
+		}
+		changes = append(changes, configChange{name: jsonName(ov.Type().Field(i)), old: o, new: n})
+	}
+	return changes
+}
+
+// keepSettings copies the settings named in keep from src into dst.
+func keepSettings(dst, src *Config, keep map[string]bool) {
+	dv, sv := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem()
+	for i := 0; i < dv.NumField(); i++ {
+		if keep[jsonName(dv.Type().Field(i))] {
+			dv.Field(i).Set(sv.Field(i))
+		}
+	}
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name
+}
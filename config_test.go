@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultConfigIsValid(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("the defaults don't validate: %v", err)
+	}
+}
+
+func TestValidateRejectsPerFrameSpeeds(t *testing.T) {
+	// set gives each speed its default from when speeds were in pixels per
+	// frame at 50 fps, times scale.
+	tests := []struct {
+		name string
+		set  func(c *Config, scale int)
+	}{
+		{"spriteVelocity", func(c *Config, scale int) { c.SpriteVelocity = 10 * scale }},
+		{"textVelocity", func(c *Config, scale int) { c.TextVelocity = 2 * scale }},
+		{"marqueeSpeed", func(c *Config, scale int) { c.MarqueeSpeed = 3 * float64(scale) }},
+		{"flockSpeed", func(c *Config, scale int) { c.FlockSpeed = 4 * float64(scale) }},
+	}
+	for _, tt := range tests {
+		c := DefaultConfig()
+		tt.set(c, 1)
+		if err := c.Validate(); err == nil || !strings.Contains(err.Error(), tt.name) {
+			t.Errorf("%s per frame: Validate() = %v, want an error about %s", tt.name, err, tt.name)
+		}
+		c = DefaultConfig()
+		tt.set(c, 50)
+		if err := c.Validate(); err != nil {
+			t.Errorf("%s converted to pixels per second: Validate() = %v", tt.name, err)
+		}
+	}
+}
+
+func TestParseConfigRejectsPerFrameSpeeds(t *testing.T) {
+	if _, err := parseConfig("old.json", []byte(`{"spriteVelocity": 10}`)); err == nil {
+		t.Error("a per-frame spriteVelocity was accepted")
+	}
+	cfg, err := parseConfig("new.json", []byte(`{"spriteVelocity": 500, "flockSpeed": 200}`))
+	if err != nil {
+		t.Fatalf("per-second speeds were refused: %v", err)
+	}
+	if cfg.SpriteVelocity != 500 || cfg.FlockSpeed != 200 {
+		t.Errorf("parsed speeds %d and %v, want 500 and 200", cfg.SpriteVelocity, cfg.FlockSpeed)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"log/slog"
+	"maps"
+	"os"
+	"time"
+)
+
+// configWatcher polls the config file's modification time once a second.
+type configWatcher struct {
+	path    string
+	modTime time.Time
+	poll    *RateLimiter
+}
+
+func newConfigWatcher(path string) *configWatcher {
+	w := &configWatcher{path: path, poll: NewRateLimiter(time.Second)}
+	if info, err := os.Stat(path); err == nil {
+		w.modTime = info.ModTime()
+	}
+	return w
+}
+
+// changed reports whether the file was modified since the last call.
+func (w *configWatcher) changed() bool {
+	if !w.poll.Allow() {
+		return false
+	}
+	info, err := os.Stat(w.path)
+	if err != nil || info.ModTime().Equal(w.modTime) {
+		return false
+	}
+	w.modTime = info.ModTime()
+	return true
+}
+
+func (g *Game) reloadConfig() {
+	cfg, err := LoadConfig(g.watcher.path)
+	if err != nil {
+		slog.Warn("ignoring config change", "err", err)
+		return
+	}
+	g.opts.override(cfg)
+	slog.Info("config reloaded", "path", g.watcher.path)
+	*g.baseConfig = *cfg
+	g.applyConfig(cfg)
+}
+
+// restartSettings only take effect the next time the game starts.
+var restartSettings = map[string]bool{
+	"windowFlags":    true,
+	"background":     true,
+	"backgroundTile": true,
+	"spriteImage":    true,
+	"spriteFrames":   true,
+	"seed":           true,
+	"fonts":          true,
+	"sounds":         true,
+	"music":          true,
+	"icons":          true,
+	"pacingMode":     true,
+	"audio":          true,
+	"pitchVariants":  true,
+}
+
+// applyConfig switches to cfg while running, logging each setting that
+// changed.
+func (g *Game) applyConfig(cfg *Config) {
+	old := *g.cfg
+	changes := configChanges(&old, cfg)
+	for _, c := range changes {
+		if restartSettings[c.name] {
+			slog.Warn("setting applies on next start", "setting", c.name, "value", c.new)
+			continue
+		}
+		slog.Info("setting changed", "setting", c.name, "old", c.old, "new", c.new)
+	}
+
+	// The menu holds pointers into g.cfg, so update it in place.
+	*g.cfg = *cfg
+	keepSettings(g.cfg, &old, restartSettings)
+
+	logLevel.Set(cfg.logLevel())
+	g.spriteVelocity = cfg.SpriteVelocity
+	g.targetFPS = cfg.TargetFPS
+	g.textXVelocity = sign(g.textXVelocity) * cfg.TextVelocity
+	g.textYVelocity = sign(g.textYVelocity) * cfg.TextVelocity
+	if cfg.TintAnimation != old.TintAnimation {
+		g.tintAnimation = cfg.TintAnimation
+	}
+	if cfg.SpriteJitter != old.SpriteJitter {
+		g.jitter = cfg.SpriteJitter
+	}
+	if cfg.ColorCycling != old.ColorCycling {
+		g.setColorCycling(cfg.ColorCycling)
+	}
+	if cfg.DebugWindow != old.DebugWindow {
+		g.setDebugWindow(cfg.DebugWindow)
+	}
+	if cfg.ShowInputs != old.ShowInputs {
+		g.showInputs = cfg.ShowInputs
+	}
+	if cfg.SplitScreen != old.SplitScreen {
+		g.splitScreen = cfg.SplitScreen
+	}
+	if cfg.HudBlur != old.HudBlur {
+		g.hudBlur = cfg.HudBlur
+	}
+	if cfg.BPMPulse != old.BPMPulse {
+		g.bpmPulse = cfg.BPMPulse
+	}
+	if cfg.DPIScaleFonts != old.DPIScaleFonts {
+		g.rescaleFonts()
+	}
+	if cfg.Afterimage != old.Afterimage {
+		g.afterimage = cfg.Afterimage
+	}
+	if cfg.TextTrail != old.TextTrail {
+		g.setTextTrail(cfg.TextTrail)
+	}
+	if cfg.TextMode != old.TextMode {
+		g.textGhosts.Reset()
+	}
+	if cfg.MaxSprites < old.MaxSprites {
+		g.evictSprites(cfg.MaxSprites)
+	}
+	if cfg.Flocking != old.Flocking {
+		g.flocking = cfg.Flocking
+	}
+	if cfg.TextColor != old.TextColor || cfg.ShadowColor != old.ShadowColor || cfg.ShadowOffsetX != old.ShadowOffsetX || cfg.ShadowOffsetY != old.ShadowOffsetY || cfg.ShadowBlur != old.ShadowBlur {
+		if err := g.renderTitle(); err != nil {
+			slog.Error("could not re-render title", "err", err)
+		}
+	}
+	if !maps.Equal(cfg.OverlayOrder, old.OverlayOrder) {
+		g.sortOverlays()
+	}
+	if cfg.ShowCrosshair != old.ShowCrosshair {
+		showCursor(!cfg.ShowCrosshair)
+	}
+	if (cfg.WindowWidth != old.WindowWidth || cfg.WindowHeight != old.WindowHeight) && !g.isFullscreen {
+		g.window.SetSize(cfg.WindowWidth, cfg.WindowHeight)
+	}
+	g.spriteAnim.fps = cfg.SpriteFPS
+	if cfg.FocusMode != old.FocusMode {
+		g.focusMode = cfg.FocusMode
+	}
+	if cfg.MaxParticles != old.MaxParticles {
+		g.particles = NewParticleSystem(cfg.MaxParticles, g.rng)
+	}
+	if cfg.FixedTimestep != old.FixedTimestep {
+		g.accumulator = 0
+	}
+	if cfg.AlwaysOnTop != old.AlwaysOnTop {
+		g.setAlwaysOnTop(cfg.AlwaysOnTop)
+	}
+	if cfg.PitchVariation && !old.PitchVariation {
+		g.makeBounceVariants()
+	}
+	if cfg.SoundChannels != old.SoundChannels {
+		g.allocateChannels(cfg.SoundChannels)
+	}
+	if cfg.Volume != old.Volume {
+		g.muted = false
+		g.setVolume(cfg.Volume)
+	}
+	g.syncMenuPause()
+}
+
+func sign(n int) int {
+	if n < 0 {
+		return -1
+	}
+	return 1
+}
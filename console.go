@@ -0,0 +1,51 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	consoleLines   = 20
+	consolePadding = 6
+)
+
+func (g *Game) scrollConsole(key sdl.Keycode) {
+	maxScroll := len(g.logs.Lines()) - consoleLines
+	switch key {
+	case sdl.K_PAGEUP:
+		g.consoleScroll = min(g.consoleScroll+consoleLines, max(maxScroll, 0))
+	case sdl.K_PAGEDOWN:
+		g.consoleScroll = max(g.consoleScroll-consoleLines, 0)
+	}
+}
+
+// renderConsole draws the most recent log lines over the top of the window,
+// colored by level.
+func (g *Game) renderConsole() {
+	lines := g.logs.Lines()
+	end := max(len(lines)-g.consoleScroll, 0)
+	start := max(end-consoleLines, 0)
+
+	lineHeight := g.lineHeight(fontConsole) + 4
+	g.drawPanel(sdl.Rect{X: 0, Y: 0, W: g.width, H: consoleLines*lineHeight + 2*consolePadding}, 200)
+
+	y := int32(consolePadding)
+	for _, line := range lines[start:end] {
+		g.drawText(fontConsole, line, logLevelColor(logLevelOf(line)), consolePadding, y)
+		y += lineHeight
+	}
+}
+
+func logLevelColor(level slog.Level) sdl.Color {
+	switch {
+	case level >= slog.LevelError:
+		return sdl.Color{R: 255, G: 80, B: 80, A: 255}
+	case level >= slog.LevelWarn:
+		return sdl.Color{R: 255, G: 220, B: 80, A: 255}
+	case level >= slog.LevelInfo:
+		return sdl.Color{R: 255, G: 255, B: 255, A: 255}
+	}
+	return sdl.Color{R: 160, G: 160, B: 160, A: 255}
+}
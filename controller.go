@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// How the left stick moves the sprite: "analog" at a speed proportional to
+// how far it is pushed, "digital" at full speed once past the threshold,
+// like the arrow keys.
+const (
+	stickAnalog  = "analog"
+	stickDigital = "digital"
+)
+
+// handleControllerDevice opens game controllers as they are plugged in and
+// closes them when they are removed.
+func (g *Game) handleControllerDevice(e *sdl.ControllerDeviceEvent) {
+	switch e.Type {
+	case sdl.CONTROLLERDEVICEADDED:
+		ctrl := sdl.GameControllerOpen(int(e.Which))
+		if ctrl == nil {
+			slog.Warn("could not open game controller", "index", e.Which, "err", sdl.GetError())
+			return
+		}
+		g.controllers[ctrl.Joystick().InstanceID()] = ctrl
+		slog.Info("game controller connected", "name", ctrl.Name())
+	case sdl.CONTROLLERDEVICEREMOVED:
+		if ctrl, ok := g.controllers[e.Which]; ok {
+			slog.Info("game controller disconnected", "name", ctrl.Name())
+			ctrl.Close()
+			delete(g.controllers, e.Which)
+		}
+	}
+}
+
+func (g *Game) closeControllers() {
+	for id, ctrl := range g.controllers {
+		ctrl.Close()
+		delete(g.controllers, id)
+	}
+}
+
+// stickValue maps a raw stick axis to -1..1. Within threshold of the center
+// it is 0. Past it, digital mode gives -1 or 1 and analog mode rises from 0
+// to 1 over the rest of the travel, so there is no jump at the threshold.
+func stickValue(raw int16, mode string, threshold float64) float64 {
+	v := max(float64(raw)/math.MaxInt16, -1)
+	if math.Abs(v) < threshold {
+		return 0
+	}
+	if mode == stickDigital {
+		return math.Copysign(1, v)
+	}
+	return math.Copysign((math.Abs(v)-threshold)/(1-threshold), v)
+}
+
+// Actions controller buttons can be bound to.
+const (
+	bindAction = "action"
+	bindMusic  = "music"
+	bindBack   = "back"
+	bindPause  = "pause"
+	bindMenu   = "menu"
+	bindSpawn  = "spawn"
+)
+
+var bindableActions = []string{bindAction, bindMusic, bindBack, bindPause, bindMenu, bindSpawn}
+
+// validateControllerBindings checks that bindings map known actions to
+// SDL controller button names, each button doing at most one thing. An
+// empty name leaves the action unbound.
+func validateControllerBindings(bindings map[string]string) error {
+	bound := make(map[string]string, len(bindings))
+	for action, button := range bindings {
+		if !slices.Contains(bindableActions, action) {
+			return fmt.Errorf("controllerBindings: unknown action %q, expected one of %s", action, strings.Join(bindableActions, ", "))
+		}
+		if button == "" {
+			continue
+		}
+		if sdl.GameControllerGetButtonFromString(button) == sdl.CONTROLLER_BUTTON_INVALID {
+			return fmt.Errorf("controllerBindings: %q is not a controller button name", button)
+		}
+		if other, ok := bound[button]; ok {
+			return fmt.Errorf("controllerBindings: %q is bound to both %q and %q", button, other, action)
+		}
+		bound[button] = action
+	}
+	return nil
+}
+
+// boundAction is the action bound to a controller button, if any.
+func (g *Game) boundAction(button sdl.GameControllerButton) string {
+	for action, name := range g.cfg.ControllerBindings {
+		if name != "" && sdl.GameControllerGetButtonFromString(name) == button {
+			return action
+		}
+	}
+	return ""
+}
+
+// handleControllerButton does what the pressed button is bound to. While
+// the quit question is up only back works, to cancel it.
+func (g *Game) handleControllerButton(e *sdl.ControllerButtonEvent) {
+	if e.Type != sdl.CONTROLLERBUTTONDOWN {
+		return
+	}
+	if g.intro {
+		g.skipIntro()
+		return
+	}
+	action := g.boundAction(sdl.GameControllerButton(e.Button))
+	if g.confirmingQuit && action != bindBack {
+		return
+	}
+	switch action {
+	case bindAction:
+		g.doAction()
+	case bindMusic:
+		g.pauseUnpauseMusic()
+	case bindBack:
+		if g.confirmingQuit {
+			g.confirmingQuit = false
+		} else {
+			g.closeOverlays()
+		}
+	case bindPause:
+		g.togglePause()
+	case bindMenu:
+		g.toggleMenu()
+	case bindSpawn:
+		g.spawnGroup()
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// showCursor shows the system cursor, or hides it for the crosshair to
+// stand in for it.
+func showCursor(on bool) {
+	toggle := sdl.ENABLE
+	if !on {
+		toggle = sdl.DISABLE
+	}
+	if _, err := sdl.ShowCursor(toggle); err != nil {
+		slog.Warn("could not change the cursor", "err", err)
+	}
+}
+
+// renderCrosshair draws a cross at the mouse, crosshairSize pixels out
+// from it each way, while the mouse is over the window. The position comes
+// from the last mouse motion, which SDL reports in logical coordinates.
+// sdl.GetMouseState would give window pixels instead, putting the cross in
+// the wrong place whenever the window isn't at its logical size.
+func (g *Game) renderCrosshair() {
+	if sdl.GetMouseFocus() != g.window {
+		return
+	}
+	x, y := g.input.Mouse()
+	n := g.cfg.CrosshairSize
+	c := g.cfg.CrosshairColor
+	r, gr, b, a, _ := g.renderer.GetDrawColor()
+	defer g.renderer.SetDrawColor(r, gr, b, a)
+	g.renderer.SetDrawColor(c.R, c.G, c.B, c.A)
+	g.renderer.DrawLine(x-n, y, x+n, y)
+	g.renderer.DrawLine(x, y-n, x, y+n)
+}
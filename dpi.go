@@ -0,0 +1,68 @@
+package main
+
+import (
+	"log/slog"
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// baseDPI is the display DPI at which fonts are opened at their configured
+// size.
+const baseDPI = 96
+
+// displayFontScale is how much to scale fonts by on a display so text is
+// the same physical size as at baseDPI, or 1 if its DPI is unknown.
+func displayFontScale(display int) float64 {
+	_, _, vdpi, err := sdl.GetDisplayDPI(display)
+	if err != nil || vdpi <= 0 {
+		slog.Debug("display DPI unknown, not scaling fonts", "display", display, "err", err)
+		return 1
+	}
+	return float64(vdpi) / baseDPI
+}
+
+// fontSize is the size to open a font configured at size at.
+func (g *Game) fontSize(size int) int {
+	return max(int(math.Round(float64(size)*g.fontScale)), 1)
+}
+
+// updateDisplay notices the window moving to another display and, with
+// dpiScaleFonts on, reopens the fonts at that display's scale. Newer SDL
+// reports the change with WINDOWEVENT_DISPLAY_CHANGED; older versions only
+// say the window moved, so both call this.
+func (g *Game) updateDisplay() {
+	display, err := g.window.GetDisplayIndex()
+	if err != nil {
+		slog.Warn("could not get the window's display", "err", err)
+		return
+	}
+	if display == g.display {
+		return
+	}
+	slog.Debug("window moved to another display", "from", g.display, "to", display)
+	g.display = display
+	g.rescaleFonts()
+}
+
+// rescaleFonts reopens the fonts and re-renders the title if the font scale
+// for the current display and dpiScaleFonts setting has changed.
+func (g *Game) rescaleFonts() {
+	scale := 1.0
+	if g.cfg.DPIScaleFonts {
+		scale = displayFontScale(g.display)
+	}
+	if scale == g.fontScale {
+		return
+	}
+	g.fontScale = scale
+	g.closeFonts()
+	if err := g.loadFonts(); err != nil {
+		slog.Error("could not reopen fonts at the new scale", "scale", scale, "err", err)
+		return
+	}
+	if err := g.renderTitle(); err != nil {
+		slog.Error("could not re-render title", "err", err)
+	}
+	slog.Info("fonts rescaled", "scale", scale)
+}
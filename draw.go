@@ -0,0 +1,112 @@
+package main
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// fillTiled covers dest with copies of tex at its native size. Tiles on the
+// right and bottom edges are cut down through their source rect, and the
+// renderer clip rect is narrowed to dest while drawing so nothing spills out.
+func fillTiled(r *sdl.Renderer, tex *sdl.Texture, dest sdl.Rect) error {
+	_, _, tw, th, err := tex.Query()
+	if err != nil {
+		return err
+	}
+
+	if r.IsClipEnabled() {
+		prev := r.GetClipRect()
+		defer r.SetClipRect(&prev)
+	} else {
+		defer r.SetClipRect(nil)
+	}
+	r.SetClipRect(&dest)
+
+	for y := dest.Y; y < dest.Y+dest.H; y += th {
+		h := min(th, dest.Y+dest.H-y)
+		for x := dest.X; x < dest.X+dest.W; x += tw {
+			w := min(tw, dest.X+dest.W-x)
+			err := r.Copy(tex, &sdl.Rect{X: 0, Y: 0, W: w, H: h}, &sdl.Rect{X: x, Y: y, W: w, H: h})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// drawRoundedRect fills rect in c with its corners rounded to radius, which
+// is clamped to half the smaller side. Each row's outermost pixels are drawn
+// at half alpha to soften the staircase.
+func drawRoundedRect(r *sdl.Renderer, rect sdl.Rect, radius int32, c sdl.Color) {
+	pr, pg, pb, pa, _ := r.GetDrawColor()
+	defer r.SetDrawColor(pr, pg, pb, pa)
+	var mode sdl.BlendMode
+	r.GetDrawBlendMode(&mode)
+	defer r.SetDrawBlendMode(mode)
+	r.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+
+	rows, edges := roundedRectShape(rect, radius)
+	r.SetDrawColor(c.R, c.G, c.B, c.A)
+	if len(rows) > 0 {
+		r.FillRects(rows)
+	}
+	if len(edges) > 0 {
+		r.SetDrawColor(c.R, c.G, c.B, c.A/2)
+		r.DrawPoints(edges)
+	}
+}
+
+// roundedRectShape splits rect with its corners rounded to radius, clamped
+// to half the smaller side, into the rows drawn solid and the pixels at the
+// ends of the corner rows drawn at half alpha. The corner arcs come from
+// cornerSpans.
+func roundedRectShape(rect sdl.Rect, radius int32) ([]sdl.Rect, []sdl.Point) {
+	radius = max(0, min(radius, rect.W/2, rect.H/2))
+	if radius == 0 {
+		return []sdl.Rect{rect}, nil
+	}
+
+	spans := cornerSpans(radius)
+	left, right := rect.X+radius, rect.X+rect.W-1-radius
+	top, bottom := rect.Y+radius, rect.Y+rect.H-1-radius
+	var rows []sdl.Rect
+	if bottom >= top {
+		rows = append(rows, sdl.Rect{X: rect.X, Y: top, W: rect.W, H: bottom - top + 1})
+	}
+	var edges []sdl.Point
+	for dy := int32(1); dy <= radius; dy++ {
+		x0, x1 := left-spans[dy], right+spans[dy]
+		if x1 < x0 {
+			continue
+		}
+		for _, y := range []int32{top - dy, bottom + dy} {
+			if x1-x0 > 1 {
+				rows = append(rows, sdl.Rect{X: x0 + 1, Y: y, W: x1 - x0 - 1, H: 1})
+			}
+			edges = append(edges, sdl.Point{X: x0, Y: y})
+			if x1 != x0 {
+				edges = append(edges, sdl.Point{X: x1, Y: y})
+			}
+		}
+	}
+	return rows, edges
+}
+
+// cornerSpans traces a circle of the given radius with the midpoint circle
+// algorithm and returns, for each row dy from 0 to radius away from the
+// center, how far the circle extends horizontally on that row.
+func cornerSpans(radius int32) []int32 {
+	spans := make([]int32, radius+1)
+	x, y := radius, int32(0)
+	d := 1 - radius
+	for x >= y {
+		spans[y] = max(spans[y], x)
+		spans[x] = max(spans[x], y)
+		y++
+		if d < 0 {
+			d += 2*y + 1
+		} else {
+			x--
+			d += 2*(y-x) + 1
+		}
+	}
+	return spans
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestCornerSpans(t *testing.T) {
+	tests := []struct {
+		radius int32
+		want   []int32
+	}{
+		{0, []int32{0}},
+		{1, []int32{1, 0}},
+		{2, []int32{2, 2, 1}},
+		{3, []int32{3, 3, 2, 1}},
+		{5, []int32{5, 5, 5, 4, 3, 2}},
+		{8, []int32{8, 8, 8, 7, 7, 6, 5, 4, 2}},
+	}
+	for _, tt := range tests {
+		if got := cornerSpans(tt.radius); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("cornerSpans(%d) = %v, want %v", tt.radius, got, tt.want)
+		}
+	}
+}
+
+// shapePixels returns every pixel roundedRectShape covers, counting how many
+// times each is drawn.
+func shapePixels(rect sdl.Rect, radius int32) map[sdl.Point]int {
+	rows, edges := roundedRectShape(rect, radius)
+	pixels := make(map[sdl.Point]int)
+	for _, r := range rows {
+		for y := r.Y; y < r.Y+r.H; y++ {
+			for x := r.X; x < r.X+r.W; x++ {
+				pixels[sdl.Point{X: x, Y: y}]++
+			}
+		}
+	}
+	for _, p := range edges {
+		pixels[p]++
+	}
+	return pixels
+}
+
+func TestRoundedRectShape(t *testing.T) {
+	rect := sdl.Rect{X: 10, Y: 20, W: 20, H: 12}
+	tests := []struct {
+		name   string
+		radius int32
+		as     int32 // the radius it has to come out the same as
+	}{
+		{"zero radius", 0, 0},
+		{"negative radius", -5, 0},
+		{"normal radius", 4, 4},
+		{"half the smaller side", 6, 6},
+		{"more than half the smaller side", 8, 6},
+		{"far more than the box", 100, 6},
+	}
+	for _, tt := range tests {
+		rows, edges := roundedRectShape(rect, tt.radius)
+		wantRows, wantEdges := roundedRectShape(rect, tt.as)
+		if !reflect.DeepEqual(rows, wantRows) || !reflect.DeepEqual(edges, wantEdges) {
+			t.Errorf("%s: radius %d isn't drawn like radius %d", tt.name, tt.radius, tt.as)
+		}
+
+		pixels := shapePixels(rect, tt.radius)
+		for p, n := range pixels {
+			if !p.InRect(&rect) {
+				t.Errorf("%s: %+v is outside %+v", tt.name, p, rect)
+			}
+			if n > 1 {
+				t.Errorf("%s: %+v is drawn %d times", tt.name, p, n)
+			}
+			mirrored := []sdl.Point{
+				{X: 2*rect.X + rect.W - 1 - p.X, Y: p.Y},
+				{X: p.X, Y: 2*rect.Y + rect.H - 1 - p.Y},
+			}
+			for _, m := range mirrored {
+				if pixels[m] == 0 {
+					t.Errorf("%s: %+v is drawn but not its mirror image %+v", tt.name, p, m)
+				}
+			}
+		}
+		center := sdl.Point{X: rect.X + rect.W/2, Y: rect.Y + rect.H/2}
+		if pixels[center] == 0 {
+			t.Errorf("%s: the center isn't drawn", tt.name)
+		}
+	}
+
+	if got, want := len(shapePixels(rect, 0)), int(rect.W*rect.H); got != want {
+		t.Errorf("zero radius draws %d pixels, want all %d", got, want)
+	}
+	rows, edges := roundedRectShape(rect, 0)
+	if !reflect.DeepEqual(rows, []sdl.Rect{rect}) || edges != nil {
+		t.Errorf("zero radius = %v, %v, want only the rect", rows, edges)
+	}
+
+	corners := []sdl.Point{
+		{X: rect.X, Y: rect.Y},
+		{X: rect.X + rect.W - 1, Y: rect.Y},
+		{X: rect.X, Y: rect.Y + rect.H - 1},
+		{X: rect.X + rect.W - 1, Y: rect.Y + rect.H - 1},
+	}
+	pixels := shapePixels(rect, 4)
+	for _, c := range corners {
+		if pixels[c] != 0 {
+			t.Errorf("corner %+v is drawn with radius 4", c)
+		}
+	}
+	// The straight sides start radius pixels in from each corner.
+	for _, p := range []sdl.Point{{X: rect.X + 4, Y: rect.Y}, {X: rect.X, Y: rect.Y + 4}} {
+		if pixels[p] == 0 {
+			t.Errorf("%+v, where the side starts, isn't drawn with radius 4", p)
+		}
+	}
+}
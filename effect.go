@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Effects that can be laid over the scene, all of them darkening it in a
+// pattern: every other row for scanlines, towards the corners for a
+// vignette, or both for an old CRT look.
+const (
+	effectNone      = "none"
+	effectScanlines = "scanlines"
+	effectVignette  = "vignette"
+	effectCRT       = "crt"
+)
+
+const (
+	// scanlineAlpha is how dark the dark rows of the scanlines are.
+	scanlineAlpha = 72
+
+	// The vignette starts vignetteStart of the way from the middle to a
+	// corner and darkens to vignetteAlpha at the corners.
+	vignetteStart = 0.45
+	vignetteAlpha = 200
+)
+
+func validateOverlayEffect(e string) error {
+	switch e {
+	case effectNone, effectScanlines, effectVignette, effectCRT:
+		return nil
+	}
+	return fmt.Errorf("overlayEffect must be %q, %q, %q or %q, got %q", effectNone, effectScanlines, effectVignette, effectCRT, e)
+}
+
+// effectShade is how dark the effect makes the pixel at x, y of a w by h
+// window, as the alpha of black drawn over it.
+func effectShade(effect string, x, y, w, h int32) uint8 {
+	var scan, vignette float64
+	if effect == effectScanlines || effect == effectCRT {
+		if y%2 == 1 {
+			scan = scanlineAlpha / 255.0
+		}
+	}
+	if effect == effectVignette || effect == effectCRT {
+		// Distance from the middle, 1 at the corners.
+		dx := (float64(x) + 0.5 - float64(w)/2) / (float64(w) / 2)
+		dy := (float64(y) + 0.5 - float64(h)/2) / (float64(h) / 2)
+		d := math.Hypot(dx, dy) / math.Sqrt2
+		t := clampf((d-vignetteStart)/(1-vignetteStart), 0, 1)
+		vignette = t * t * vignetteAlpha / 255
+	}
+	// Layered over each other, each darkens what the other lets through.
+	return uint8(math.Round((1 - (1-scan)*(1-vignette)) * 255))
+}
+
+// createEffect draws the effect for the current window size into a texture
+// once, so that showing it costs one copy per frame.
+func (g *Game) createEffect(effect string) (*sdl.Texture, error) {
+	s, err := sdl.CreateRGBSurfaceWithFormat(0, g.width, g.height, 32, sdl.PIXELFORMAT_ARGB8888)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating effect surface: %v", err)
+	}
+	defer s.Free()
+	pix, pitch := s.Pixels(), int(s.Pitch)
+	for y := int32(0); y < g.height; y++ {
+		for x := int32(0); x < g.width; x++ {
+			// ARGB8888 is stored B, G, R, A; leaving the color black.
+			pix[int(y)*pitch+int(x)*4+3] = effectShade(effect, x, y, g.width, g.height)
+		}
+	}
+	t, err := g.renderer.CreateTextureFromSurface(s)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating effect texture: %v", err)
+	}
+	t.SetBlendMode(sdl.BLENDMODE_BLEND)
+	return t, nil
+}
+
+// renderEffect lays the configured effect over the scene, making its
+// texture again when the effect or window size changed. If it can't be made
+// the effect is turned off.
+func (g *Game) renderEffect() {
+	if g.effect == nil || g.effectName != g.cfg.OverlayEffect {
+		g.freeEffect()
+		t, err := g.createEffect(g.cfg.OverlayEffect)
+		if err != nil {
+			slog.Warn("could not create the overlay effect, turning it off", "effect", g.cfg.OverlayEffect, "err", err)
+			g.cfg.OverlayEffect = effectNone
+			return
+		}
+		g.effect, g.effectName = t, g.cfg.OverlayEffect
+	}
+	g.renderer.Copy(g.effect, nil, nil)
+}
+
+// freeEffect frees the effect texture, which is made again at the current
+// size when next drawn.
+func (g *Game) freeEffect() {
+	if g.effect != nil {
+		g.effect.Destroy()
+		g.effect = nil
+	}
+}
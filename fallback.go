@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/veandco/go-sdl2/mix"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// placeholderColor is what the sprite is drawn as when its image is missing.
+var placeholderColor = sdl.Color{R: 255, G: 0, B: 255, A: 255}
+
+// assetFailed records an asset that couldn't be loaded and is being done
+// without, for Init to report once it has loaded what it can.
+func (g *Game) assetFailed(err error) {
+	g.loadErrors = append(g.loadErrors, err)
+}
+
+// reportLoadErrors logs every asset that failed to load in one warning.
+func (g *Game) reportLoadErrors() {
+	if len(g.loadErrors) == 0 {
+		return
+	}
+	slog.Warn("some assets could not be loaded, running without them", "count", len(g.loadErrors), "errors", errors.Join(g.loadErrors...))
+}
+
+// loadSilentSound stands in for a sound that failed to load with a short
+// silent one, so that playing it still takes a channel as usual.
+func (g *Game) loadSilentSound(name string) (*mix.Chunk, error) {
+	freq, _, channels, _, err := mix.QuerySpec()
+	if err != nil {
+		return nil, err
+	}
+	frames := freq / 100
+	return g.assets.LoadSoundData(name, "", encodeWAV(make([]float64, frames*channels), freq, channels))
+}
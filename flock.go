@@ -0,0 +1,74 @@
+package main
+
+// flockMaxForce caps how fast a single rule can change a sprite's velocity,
+// in pixels per second per second, which keeps the turns smooth.
+const flockMaxForce = 375.0
+
+// flock steers the spawned sprites with the boids rules: separation from
+// close neighbors, alignment with their heading, cohesion towards their
+// center, plus following the player as the leader. All steering is worked
+// out before any velocity changes so the result doesn't depend on order.
+// dt is how many seconds' worth of steering to apply.
+func (g *Game) flock(sprites []*Sprite, dt float64) {
+	cfg := g.cfg
+	radius := cfg.FlockRadius
+	if g.grid == nil || g.grid.size != radius {
+		g.grid = newSpatialGrid(radius)
+	}
+	g.grid.Reset()
+	for _, s := range sprites {
+		g.grid.Insert(s)
+	}
+
+	leader := g.player.box.Center()
+	speed := cfg.FlockSpeed
+	if cap(g.steering) < len(sprites) {
+		g.steering = make([]Vec2, len(sprites))
+	}
+	g.steering = g.steering[:len(sprites)]
+	clear(g.steering)
+	for i, s := range sprites {
+		c := s.box.Center()
+		g.neighbors = g.grid.Query(AABB{X: c.X - radius, Y: c.Y - radius, W: 2 * radius, H: 2 * radius}, g.neighbors[:0])
+
+		var separation, alignment, center Vec2
+		n := 0
+		for _, o := range g.neighbors {
+			if o == s {
+				continue
+			}
+			away := c.Sub(o.box.Center())
+			dist := away.Len()
+			if dist >= radius {
+				continue
+			}
+			n++
+			if dist > 0 {
+				separation = separation.Add(away.Scale(1 / (dist * dist)))
+			}
+			alignment = alignment.Add(o.vel)
+			center = center.Add(o.box.Center())
+		}
+
+		var force Vec2
+		if n > 0 {
+			force = force.Add(steer(separation, s.vel, speed).Scale(cfg.SeparationWeight))
+			force = force.Add(steer(alignment, s.vel, speed).Scale(cfg.AlignmentWeight))
+			force = force.Add(steer(center.Scale(1/float64(n)).Sub(c), s.vel, speed).Scale(cfg.CohesionWeight))
+		}
+		force = force.Add(steer(leader.Sub(c), s.vel, speed).Scale(cfg.LeaderWeight))
+		g.steering[i] = force
+	}
+	for i, s := range sprites {
+		s.vel = s.vel.Add(g.steering[i].Scale(dt)).Limit(speed)
+	}
+}
+
+// steer returns the acceleration that turns vel towards dir at full speed,
+// capped at flockMaxForce.
+func steer(dir, vel Vec2, speed float64) Vec2 {
+	if dir.Len() == 0 {
+		return Vec2{}
+	}
+	return dir.Normalized().Scale(speed).Sub(vel).Limit(flockMaxForce)
+}
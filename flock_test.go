@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestFlockDoesNotAllocate(t *testing.T) {
+	g := &Game{cfg: DefaultConfig(), player: &Sprite{box: AABB{X: 400, Y: 300, W: 128, H: 128}}}
+	var sprites []*Sprite
+	for i := 0; i < 50; i++ {
+		sprites = append(sprites, &Sprite{
+			box: AABB{X: float64(i%10) * 40, Y: float64(i/10) * 40, W: spawnSize, H: spawnSize},
+			vel: Vec2{X: 60, Y: float64(i)},
+		})
+	}
+	// The first pass makes the grid and the buffers it reuses after.
+	g.flock(sprites, 1.0/60)
+	if allocs := testing.AllocsPerRun(100, func() { g.flock(sprites, 1.0/60) }); allocs != 0 {
+		t.Errorf("flocking %d sprites makes %v allocations a frame, want 0", len(sprites), allocs)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+// Logical font names. Text asking for a font that isn't configured is drawn
+// with fontUI instead.
+const (
+	fontTitle   = "title"
+	fontUI      = "ui"
+	fontConsole = "console"
+)
+
+// FontConfig describes one font to open at startup.
+type FontConfig struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Size int    `json:"size"`
+}
+
+// loadFonts opens every configured font it can, returning the errors for
+// those it couldn't.
+func (g *Game) loadFonts() error {
+	var errs []error
+	for _, fc := range g.cfg.Fonts {
+		if _, err := g.assets.LoadFont(fc.Name, fc.Path, g.fontSize(fc.Size)); err != nil {
+			errs = append(errs, fmt.Errorf("Error loading font %q: %v", fc.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (g *Game) closeFonts() {
+	g.assets.Free(assetFont)
+}
+
+// font returns the font with the given logical name, or the UI font if there
+// is none. It is nil when neither could be loaded, and text needing it isn't
+// drawn.
+func (g *Game) font(name string) *ttf.Font {
+	if font := g.assets.Font(name); font != nil {
+		return font
+	}
+	if !g.missingFonts[name] {
+		slog.Debug("font not configured, using default", "font", name, "default", fontUI)
+		g.missingFonts[name] = true
+	}
+	return g.assets.Font(fontUI)
+}
+
+func (g *Game) lineHeight(name string) int32 {
+	font := g.font(name)
+	if font == nil {
+		return 0
+	}
+	return int32(font.Height())
+}
+
+// drawText renders s at x, y in the main window. The texture is created and
+// destroyed on every call, which is fine for the few lines of debug text
+// drawn per frame.
+func (g *Game) drawText(fontName, s string, c sdl.Color, x, y int32) error {
+	return g.drawTextOn(g.renderer, fontName, s, c, x, y)
+}
+
+// drawTextOn is drawText for any window's renderer.
+func (g *Game) drawTextOn(r *sdl.Renderer, fontName, s string, c sdl.Color, x, y int32) error {
+	font := g.font(fontName)
+	if s == "" || font == nil {
+		return nil
+	}
+	surface, err := font.RenderUTF8Blended(s, c)
+	if err != nil {
+		return err
+	}
+	defer surface.Free()
+
+	texture, err := r.CreateTextureFromSurface(surface)
+	if err != nil {
+		return err
+	}
+	defer texture.Destroy()
+	if c.A < 255 {
+		texture.SetAlphaMod(c.A)
+	}
+
+	return r.Copy(texture, nil, &sdl.Rect{X: x, Y: y, W: surface.W, H: surface.H})
+}
+
+// renderTextWithShadow renders s in fg on top of a drop shadow offset by
+// offX, offY and returns both baked into one texture, padded so the shadow
+// fits. The shadow is softened by stamping it at every offset within
+// cfg.ShadowBlur pixels, each stamp faint enough that where all of them
+// overlap the shadow reaches its full alpha. A transparent shadow renders the
+// text alone.
+func (g *Game) renderTextWithShadow(fontName, s string, fg, shadow sdl.Color, offX, offY int32) (*sdl.Texture, error) {
+	font := g.font(fontName)
+	text, err := font.RenderUTF8Blended(s, fg)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating font surface: %v", err)
+	}
+	defer text.Free()
+	if shadow.A == 0 {
+		return g.createTexture(text)
+	}
+
+	shade, err := font.RenderUTF8Blended(s, sdl.Color{R: shadow.R, G: shadow.G, B: shadow.B, A: 255})
+	if err != nil {
+		return nil, fmt.Errorf("Error creating font surface: %v", err)
+	}
+	defer shade.Free()
+
+	blur := g.cfg.ShadowBlur
+	var stamps []sdl.Point
+	for dy := -blur; dy <= blur; dy++ {
+		for dx := -blur; dx <= blur; dx++ {
+			if dx*dx+dy*dy <= blur*blur {
+				stamps = append(stamps, sdl.Point{X: dx, Y: dy})
+			}
+		}
+	}
+	alpha := 1 - math.Pow(1-float64(shadow.A)/255, 1/float64(len(stamps)))
+	shade.SetAlphaMod(uint8(math.Max(1, math.Round(alpha*255))))
+
+	tx, ty := blur+max(0, -offX), blur+max(0, -offY)
+	out, err := sdl.CreateRGBSurfaceWithFormat(0, text.W+abs32(offX)+2*blur, text.H+abs32(offY)+2*blur, 32, sdl.PIXELFORMAT_ARGB8888)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating shadow surface: %v", err)
+	}
+	defer out.Free()
+	for _, p := range stamps {
+		shade.Blit(nil, out, &sdl.Rect{X: tx + offX + p.X, Y: ty + offY + p.Y})
+	}
+	text.Blit(nil, out, &sdl.Rect{X: tx, Y: ty})
+	return g.createTexture(out)
+}
+
+func (g *Game) createTexture(surface *sdl.Surface) (*sdl.Texture, error) {
+	texture, err := g.renderer.CreateTextureFromSurface(surface)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating font texture: %v", err)
+	}
+	return texture, nil
+}
+
+func abs32(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
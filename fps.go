@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const fpsPadding = 8
+
+// fps is the frame rate averaged over the last timingSamples frames.
+func (g *Game) fps() float64 {
+	ms := g.timings.frame.Value()
+	if ms == 0 {
+		return 0
+	}
+	return 1000 / ms
+}
+
+// renderFPS shows the frame rate in a panel in the top left corner.
+func (g *Game) renderFPS() {
+	label := fmt.Sprintf("%.0f FPS", g.fps())
+	font := g.font(fontUI)
+	if font == nil {
+		return
+	}
+	w, _, err := font.SizeUTF8(label)
+	if err != nil {
+		return
+	}
+	panel := sdl.Rect{X: fpsPadding, Y: fpsPadding, W: int32(w) + 2*fpsPadding, H: g.lineHeight(fontUI) + 2*fpsPadding}
+	g.drawPanel(panel, 160)
+	g.drawText(fontUI, label, sdl.Color{R: 255, G: 255, B: 255, A: 255}, panel.X+fpsPadding, panel.Y+fpsPadding)
+}
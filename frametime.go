@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"slices"
+)
+
+const (
+	// frameTimeLogSize caps the frame times kept for -frametime-log, about
+	// 20 minutes at 50 fps. Older ones are overwritten.
+	frameTimeLogSize = 1 << 16
+
+	// frameTimeBucket is the width in ms of the histogram buckets logged
+	// with the percentiles.
+	frameTimeBucket = 4
+)
+
+// frameTimeLog records the time between presented frames in a ring buffer
+// so it can be exported to path for offline analysis, or only timed with no
+// path.
+type frameTimeLog struct {
+	path    string
+	samples []float64 // ms
+	next    int
+	total   int    // frames recorded, including overwritten ones
+	last    uint64 // performance counter at the last present
+}
+
+func newFrameTimeLog(path string) *frameTimeLog {
+	return &frameTimeLog{path: path, samples: make([]float64, 0, frameTimeLogSize)}
+}
+
+// Presented records a frame presented at performance counter now.
+func (l *frameTimeLog) Presented(now uint64) {
+	if l.last != 0 {
+		l.add(perfMillis(l.last, now))
+	}
+	l.last = now
+}
+
+func (l *frameTimeLog) add(ms float64) {
+	if len(l.samples) < cap(l.samples) {
+		l.samples = append(l.samples, ms)
+	} else {
+		l.samples[l.next] = ms
+	}
+	l.next = (l.next + 1) % cap(l.samples)
+	l.total++
+}
+
+// Samples returns the recorded frame times, oldest first.
+func (l *frameTimeLog) Samples() []float64 {
+	if len(l.samples) < cap(l.samples) {
+		return slices.Clone(l.samples)
+	}
+	return append(slices.Clone(l.samples[l.next:]), l.samples[:l.next]...)
+}
+
+// WriteCSV writes the recorded frame times as "frame,ms" rows, numbering
+// frames from the start of the game.
+func (l *frameTimeLog) WriteCSV() error {
+	f, err := os.Create(l.path)
+	if err != nil {
+		return fmt.Errorf("Error creating frame time log: %v", err)
+	}
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "frame,ms")
+	samples := l.Samples()
+	first := l.total - len(samples) + 1
+	for i, ms := range samples {
+		fmt.Fprintf(w, "%d,%.3f\n", first+i, ms)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("Error writing frame time log: %v", err)
+	}
+	return f.Close()
+}
+
+// percentile returns the p-th percentile (0-100) of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[max(0, min(i, len(sorted)-1))]
+}
+
+// writeFrameTimes exports the frame times to the -frametime-log file and
+// logs their percentiles and histogram.
+func (g *Game) writeFrameTimes() {
+	l := g.frameTimes
+	if l == nil || l.path == "" {
+		return
+	}
+	if err := l.WriteCSV(); err != nil {
+		slog.Error("could not write frame times", "path", l.path, "err", err)
+		return
+	}
+	sorted := l.Samples()
+	slices.Sort(sorted)
+	slog.Info("frame times written", "path", l.path, "frames", len(sorted),
+		"p50", percentile(sorted, 50), "p95", percentile(sorted, 95), "p99", percentile(sorted, 99))
+
+	counts := make(map[int]int)
+	for _, ms := range sorted {
+		counts[int(ms/frameTimeBucket)]++
+	}
+	buckets := make([]int, 0, len(counts))
+	for b := range counts {
+		buckets = append(buckets, b)
+	}
+	slices.Sort(buckets)
+	for _, b := range buckets {
+		slog.Info("frame time histogram", "ms", fmt.Sprintf("%d-%d", b*frameTimeBucket, (b+1)*frameTimeBucket), "frames", counts[b])
+	}
+}
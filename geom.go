@@ -0,0 +1,114 @@
+package main
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type Vec2 struct {
+	X, Y float64
+}
+
+func (v Vec2) Add(o Vec2) Vec2      { return Vec2{X: v.X + o.X, Y: v.Y + o.Y} }
+func (v Vec2) Sub(o Vec2) Vec2      { return Vec2{X: v.X - o.X, Y: v.Y - o.Y} }
+func (v Vec2) Scale(f float64) Vec2 { return Vec2{X: v.X * f, Y: v.Y * f} }
+func (v Vec2) Len() float64         { return math.Hypot(v.X, v.Y) }
+
+// Normalized returns v scaled to length 1, or the zero vector unchanged.
+func (v Vec2) Normalized() Vec2 {
+	l := v.Len()
+	if l == 0 {
+		return v
+	}
+	return v.Scale(1 / l)
+}
+
+// Limit shortens v to at most max, keeping its direction.
+func (v Vec2) Limit(max float64) Vec2 {
+	if l := v.Len(); l > max {
+		return v.Scale(max / l)
+	}
+	return v
+}
+
+// AABB is an axis-aligned bounding box with float coordinates for collision
+// math, where sdl.Rect's int32 fields are awkward. Boxes are half-open: a
+// box covers [X, X+W) horizontally, so boxes that only share an edge do not
+// overlap.
+type AABB struct {
+	X, Y, W, H float64
+}
+
+func AABBFromRect(r sdl.Rect) AABB {
+	return AABB{X: float64(r.X), Y: float64(r.Y), W: float64(r.W), H: float64(r.H)}
+}
+
+// Rect converts the box to an sdl.Rect, rounding to the nearest pixel.
+func (a AABB) Rect() sdl.Rect {
+	return sdl.Rect{
+		X: int32(math.Round(a.X)),
+		Y: int32(math.Round(a.Y)),
+		W: int32(math.Round(a.W)),
+		H: int32(math.Round(a.H)),
+	}
+}
+
+func (a AABB) FRect() sdl.FRect {
+	return sdl.FRect{X: float32(a.X), Y: float32(a.Y), W: float32(a.W), H: float32(a.H)}
+}
+
+func (a AABB) Right() float64  { return a.X + a.W }
+func (a AABB) Bottom() float64 { return a.Y + a.H }
+
+func (a AABB) Center() Vec2 {
+	return Vec2{X: a.X + a.W/2, Y: a.Y + a.H/2}
+}
+
+func (a AABB) Empty() bool {
+	return a.W <= 0 || a.H <= 0
+}
+
+// Intersects reports whether the boxes share any area.
+func (a AABB) Intersects(b AABB) bool {
+	return !a.Empty() && !b.Empty() &&
+		a.X < b.Right() && b.X < a.Right() &&
+		a.Y < b.Bottom() && b.Y < a.Bottom()
+}
+
+// rectsOverlap reports whether two rects share any area, by the same rule as
+// AABB.Intersects: rects that only touch along an edge don't.
+func rectsOverlap(a, b *sdl.Rect) bool {
+	return AABBFromRect(*a).Intersects(AABBFromRect(*b))
+}
+
+func (a AABB) Contains(p Vec2) bool {
+	return p.X >= a.X && p.X < a.Right() && p.Y >= a.Y && p.Y < a.Bottom()
+}
+
+// ContainsBox reports whether b lies entirely inside a.
+func (a AABB) ContainsBox(b AABB) bool {
+	return b.X >= a.X && b.Right() <= a.Right() && b.Y >= a.Y && b.Bottom() <= a.Bottom()
+}
+
+// Union returns the smallest box containing both boxes. Empty boxes are
+// ignored.
+func (a AABB) Union(b AABB) AABB {
+	if a.Empty() {
+		return b
+	}
+	if b.Empty() {
+		return a
+	}
+	x, y := math.Min(a.X, b.X), math.Min(a.Y, b.Y)
+	return AABB{X: x, Y: y, W: math.Max(a.Right(), b.Right()) - x, H: math.Max(a.Bottom(), b.Bottom()) - y}
+}
+
+// Overlap returns the area shared by both boxes, if any.
+func (a AABB) Overlap(b AABB) (AABB, bool) {
+	if !a.Intersects(b) {
+		return AABB{}, false
+	}
+	x, y := math.Max(a.X, b.X), math.Max(a.Y, b.Y)
+	return AABB{X: x, Y: y, W: math.Min(a.Right(), b.Right()) - x, H: math.Min(a.Bottom(), b.Bottom()) - y}, true
+}
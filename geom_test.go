@@ -0,0 +1,159 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestAABBIntersects(t *testing.T) {
+	a := AABB{X: 0, Y: 0, W: 10, H: 10}
+	tests := []struct {
+		name string
+		b    AABB
+		want bool
+	}{
+		{"overlapping", AABB{X: 5, Y: 5, W: 10, H: 10}, true},
+		{"inside", AABB{X: 2, Y: 2, W: 4, H: 4}, true},
+		{"around", AABB{X: -5, Y: -5, W: 20, H: 20}, true},
+		{"same", a, true},
+		{"touching right edge", AABB{X: 10, Y: 0, W: 10, H: 10}, false},
+		{"touching bottom edge", AABB{X: 0, Y: 10, W: 10, H: 10}, false},
+		{"touching left edge", AABB{X: -10, Y: 0, W: 10, H: 10}, false},
+		{"touching corner", AABB{X: 10, Y: 10, W: 10, H: 10}, false},
+		{"apart", AABB{X: 20, Y: 20, W: 5, H: 5}, false},
+		{"zero width inside", AABB{X: 5, Y: 5, W: 0, H: 4}, false},
+		{"zero size inside", AABB{X: 5, Y: 5}, false},
+		{"negative size", AABB{X: 5, Y: 5, W: -2, H: 4}, false},
+	}
+	for _, tt := range tests {
+		if got := a.Intersects(tt.b); got != tt.want {
+			t.Errorf("%s: a.Intersects(%+v) = %v, want %v", tt.name, tt.b, got, tt.want)
+		}
+		if got := tt.b.Intersects(a); got != tt.want {
+			t.Errorf("%s: %+v.Intersects(a) = %v, want %v", tt.name, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAABBContains(t *testing.T) {
+	a := AABB{X: 0, Y: 0, W: 10, H: 10}
+	tests := []struct {
+		p    Vec2
+		want bool
+	}{
+		{Vec2{X: 5, Y: 5}, true},
+		{Vec2{X: 0, Y: 0}, true},
+		{Vec2{X: 9.9, Y: 9.9}, true},
+		{Vec2{X: 10, Y: 5}, false},
+		{Vec2{X: 5, Y: 10}, false},
+		{Vec2{X: -0.1, Y: 5}, false},
+	}
+	for _, tt := range tests {
+		if got := a.Contains(tt.p); got != tt.want {
+			t.Errorf("Contains(%+v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if (AABB{}).Contains(Vec2{}) {
+		t.Error("a zero size box contains its corner")
+	}
+}
+
+func TestAABBContainsBox(t *testing.T) {
+	a := AABB{X: 0, Y: 0, W: 10, H: 10}
+	tests := []struct {
+		name string
+		b    AABB
+		want bool
+	}{
+		{"inside", AABB{X: 2, Y: 2, W: 4, H: 4}, true},
+		{"same", a, true},
+		{"inside touching an edge", AABB{X: 5, Y: 0, W: 5, H: 5}, true},
+		{"overlapping", AABB{X: 5, Y: 5, W: 10, H: 10}, false},
+		{"around", AABB{X: -5, Y: -5, W: 20, H: 20}, false},
+		{"outside touching an edge", AABB{X: 10, Y: 0, W: 5, H: 5}, false},
+	}
+	for _, tt := range tests {
+		if got := a.ContainsBox(tt.b); got != tt.want {
+			t.Errorf("%s: ContainsBox(%+v) = %v, want %v", tt.name, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAABBOverlap(t *testing.T) {
+	a := AABB{X: 0, Y: 0, W: 10, H: 10}
+	tests := []struct {
+		name string
+		b    AABB
+		want AABB
+		ok   bool
+	}{
+		{"overlapping", AABB{X: 5, Y: 4, W: 10, H: 10}, AABB{X: 5, Y: 4, W: 5, H: 6}, true},
+		{"inside", AABB{X: 2, Y: 2, W: 4, H: 4}, AABB{X: 2, Y: 2, W: 4, H: 4}, true},
+		{"around", AABB{X: -5, Y: -5, W: 20, H: 20}, a, true},
+		{"touching an edge", AABB{X: 10, Y: 0, W: 10, H: 10}, AABB{}, false},
+		{"zero size", AABB{X: 5, Y: 5}, AABB{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := a.Overlap(tt.b)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: Overlap(%+v) = %+v, %v, want %+v, %v", tt.name, tt.b, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAABBUnion(t *testing.T) {
+	a := AABB{X: 0, Y: 0, W: 10, H: 10}
+	tests := []struct {
+		name string
+		b    AABB
+		want AABB
+	}{
+		{"apart", AABB{X: 20, Y: 30, W: 5, H: 5}, AABB{X: 0, Y: 0, W: 25, H: 35}},
+		{"overlapping", AABB{X: -5, Y: 5, W: 10, H: 10}, AABB{X: -5, Y: 0, W: 15, H: 15}},
+		{"inside", AABB{X: 2, Y: 2, W: 4, H: 4}, a},
+		{"touching an edge", AABB{X: 10, Y: 0, W: 10, H: 10}, AABB{X: 0, Y: 0, W: 20, H: 10}},
+		{"zero size", AABB{X: 50, Y: 50}, a},
+	}
+	for _, tt := range tests {
+		if got := a.Union(tt.b); got != tt.want {
+			t.Errorf("%s: a.Union(%+v) = %+v, want %+v", tt.name, tt.b, got, tt.want)
+		}
+		if got := tt.b.Union(a); got != tt.want {
+			t.Errorf("%s: %+v.Union(a) = %+v, want %+v", tt.name, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAABBRect(t *testing.T) {
+	r := sdl.Rect{X: -3, Y: 4, W: 20, H: 7}
+	if got := AABBFromRect(r).Rect(); got != r {
+		t.Errorf("AABBFromRect(%+v).Rect() = %+v", r, got)
+	}
+	if got := (AABB{X: 1.4, Y: 1.6, W: 2.5, H: 3.49}).Rect(); got != (sdl.Rect{X: 1, Y: 2, W: 3, H: 3}) {
+		t.Errorf("Rect() rounded to %+v", got)
+	}
+}
+
+func TestRectsOverlap(t *testing.T) {
+	a := sdl.Rect{X: 0, Y: 0, W: 10, H: 10}
+	tests := []struct {
+		name string
+		b    sdl.Rect
+		want bool
+	}{
+		{"overlapping", sdl.Rect{X: 5, Y: 5, W: 10, H: 10}, true},
+		{"inside", sdl.Rect{X: 2, Y: 2, W: 4, H: 4}, true},
+		{"touching an edge", sdl.Rect{X: 10, Y: 0, W: 10, H: 10}, false},
+		{"apart", sdl.Rect{X: -30, Y: 0, W: 10, H: 10}, false},
+		{"zero size", sdl.Rect{X: 5, Y: 5}, false},
+	}
+	for _, tt := range tests {
+		if got := rectsOverlap(&a, &tt.b); got != tt.want {
+			t.Errorf("%s: rectsOverlap(%+v, %+v) = %v, want %v", tt.name, a, tt.b, got, tt.want)
+		}
+		if got := rectsOverlap(&tt.b, &a); got != tt.want {
+			t.Errorf("%s: rectsOverlap(%+v, %+v) = %v, want %v", tt.name, tt.b, a, got, tt.want)
+		}
+	}
+}
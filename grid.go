@@ -0,0 +1,56 @@
+package main
+
+import "math"
+
+type gridCell struct {
+	x, y int
+}
+
+// spatialGrid buckets sprites by the cell their center falls in, so neighbor
+// queries only look at nearby sprites instead of all of them.
+type spatialGrid struct {
+	size  float64
+	cells map[gridCell][]*Sprite
+}
+
+func newSpatialGrid(size float64) *spatialGrid {
+	return &spatialGrid{size: size, cells: make(map[gridCell][]*Sprite)}
+}
+
+func (g *spatialGrid) cellAt(p Vec2) gridCell {
+	return gridCell{x: int(math.Floor(p.X / g.size)), y: int(math.Floor(p.Y / g.size))}
+}
+
+// Reset empties the grid, keeping the cell slices around for reuse.
+func (g *spatialGrid) Reset() {
+	for c, sprites := range g.cells {
+		g.cells[c] = sprites[:0]
+	}
+}
+
+func (g *spatialGrid) Insert(s *Sprite) {
+	c := g.cellAt(s.box.Center())
+	g.cells[c] = append(g.cells[c], s)
+}
+
+// Query appends to buf the sprites whose center lies inside area. Cells
+// entirely inside it are taken whole, without checking each sprite.
+func (g *spatialGrid) Query(area AABB, buf []*Sprite) []*Sprite {
+	lo := g.cellAt(Vec2{X: area.X, Y: area.Y})
+	hi := g.cellAt(Vec2{X: area.Right(), Y: area.Bottom()})
+	for x := lo.x; x <= hi.x; x++ {
+		for y := lo.y; y <= hi.y; y++ {
+			cell := g.cells[gridCell{x: x, y: y}]
+			if area.ContainsBox(AABB{X: float64(x) * g.size, Y: float64(y) * g.size, W: g.size, H: g.size}) {
+				buf = append(buf, cell...)
+				continue
+			}
+			for _, s := range cell {
+				if area.Contains(s.box.Center()) {
+					buf = append(buf, s)
+				}
+			}
+		}
+	}
+	return buf
+}
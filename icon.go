@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// IconConfig is one size of the window icon. Size is the image's width and
+// height in pixels.
+type IconConfig struct {
+	Path string `json:"path"`
+	Size int    `json:"size"`
+}
+
+// preferredIconSize is the icon size the platform shows best: small in the
+// Windows title bar, large in the macOS dock and in between elsewhere.
+func preferredIconSize() int {
+	switch sdl.GetPlatform() {
+	case "Windows":
+		return 32
+	case "Mac OS X":
+		return 256
+	}
+	return 64
+}
+
+// iconCandidates orders icons best first for the wanted size: the smallest
+// that is at least that big, then bigger ones, then smaller ones from the
+// biggest down, since scaling down looks better than scaling up.
+func iconCandidates(icons []IconConfig, want int) []IconConfig {
+	rank := func(ic IconConfig) (bool, int) {
+		if ic.Size >= want {
+			return false, ic.Size - want
+		}
+		return true, want - ic.Size
+	}
+	sorted := append([]IconConfig(nil), icons...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, di := rank(sorted[i])
+		sj, dj := rank(sorted[j])
+		if si != sj {
+			return !si
+		}
+		return di < dj
+	})
+	return sorted
+}
+
+// loadIcon loads the best icon for the platform, falling back to the next
+// best when a file can't be loaded.
+func (g *Game) loadIcon() (*sdl.Surface, error) {
+	want := preferredIconSize()
+	for _, ic := range iconCandidates(g.cfg.Icons, want) {
+		icon, err := g.assets.LoadSurface("icon", ic.Path)
+		if err != nil {
+			slog.Warn("could not load icon, trying the next size", "path", ic.Path, "err", err)
+			continue
+		}
+		slog.Debug("window icon", "path", ic.Path, "size", ic.Size, "preferred", want)
+		return icon, nil
+	}
+	return nil, fmt.Errorf("Error loading icon image: none of the %d configured icons could be loaded", len(g.cfg.Icons))
+}
+
+func validateIcons(icons []IconConfig) error {
+	if len(icons) == 0 {
+		return fmt.Errorf("icons: at least one icon is required")
+	}
+	for _, ic := range icons {
+		if ic.Path == "" {
+			return fmt.Errorf("icons: every icon needs a path")
+		}
+		if ic.Size <= 0 {
+			return fmt.Errorf("icons: size of %q must be positive, got %d", ic.Path, ic.Size)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// idleDimFadeSeconds is how long the screen takes to dim once idle.
+const idleDimFadeSeconds = 3.0
+
+// noteInput records when the player last did something, for dimming the
+// screen when idle. Controller sticks only count when pushed past the
+// threshold, so a drifting stick doesn't keep the screen bright.
+func (g *Game) noteInput(event sdl.Event) {
+	switch e := event.(type) {
+	case *sdl.KeyboardEvent, *sdl.MouseMotionEvent, *sdl.MouseButtonEvent, *sdl.MouseWheelEvent, *sdl.ControllerButtonEvent:
+	case *sdl.ControllerAxisEvent:
+		if stickValue(e.Value, stickAnalog, g.cfg.StickThreshold) == 0 {
+			return
+		}
+	default:
+		return
+	}
+	g.lastInput = sdl.GetTicks()
+}
+
+// idleDimAlpha is how dark the screen is made after IdleDimSeconds without
+// input: nothing at first, then fading over idleDimFadeSeconds down to
+// IdleDimLevel brightness.
+func (g *Game) idleDimAlpha() uint8 {
+	if g.cfg.IdleDimSeconds <= 0 {
+		return 0
+	}
+	idle := float64(sdl.GetTicks()-g.lastInput)/1000 - g.cfg.IdleDimSeconds
+	if idle <= 0 {
+		return 0
+	}
+	progress := min(idle/idleDimFadeSeconds, 1)
+	return uint8(lerp(0, 255*(1-g.cfg.IdleDimLevel), progress))
+}
+
+// renderIdleDim darkens everything drawn so far while idle.
+func (g *Game) renderIdleDim() {
+	if alpha := g.idleDimAlpha(); alpha > 0 {
+		g.dimScreen(alpha)
+	}
+}
+
+// dimScreen darkens everything drawn so far by covering it in black at
+// alpha, keeping the draw color and blend mode.
+func (g *Game) dimScreen(alpha uint8) {
+	r, gr, b, a, _ := g.renderer.GetDrawColor()
+	var mode sdl.BlendMode
+	g.renderer.GetDrawBlendMode(&mode)
+	defer g.renderer.SetDrawColor(r, gr, b, a)
+	defer g.renderer.SetDrawBlendMode(mode)
+
+	g.renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+	g.renderer.SetDrawColor(0, 0, 0, alpha)
+	g.renderer.FillRect(nil)
+}
@@ -0,0 +1,11 @@
+package main
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// ImageConfig is an image file to load. ColorKey, when set, is a color
+// drawn as transparent, for images that use one in place of an alpha
+// channel; its alpha is ignored.
+type ImageConfig struct {
+	Path     string     `json:"path"`
+	ColorKey *sdl.Color `json:"colorKey"`
+}
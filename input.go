@@ -0,0 +1,75 @@
+package main
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// InputManager tracks keyboard, mouse and controller state from events, so
+// game code can ask which keys are held, which were pressed this frame,
+// where the mouse is and where the controller sticks are.
+type InputManager struct {
+	down        map[sdl.Scancode]bool
+	justPressed []sdl.Keysym
+	mouse       sdl.Point
+	axes        map[sdl.GameControllerAxis]int16
+}
+
+func NewInputManager() *InputManager {
+	return &InputManager{down: make(map[sdl.Scancode]bool), axes: make(map[sdl.GameControllerAxis]int16)}
+}
+
+// Handle updates the state from an event. Other event types are ignored.
+func (m *InputManager) Handle(event sdl.Event) {
+	switch e := event.(type) {
+	case *sdl.MouseMotionEvent:
+		m.mouse = sdl.Point{X: e.X, Y: e.Y}
+		return
+	case *sdl.ControllerAxisEvent:
+		m.axes[sdl.GameControllerAxis(e.Axis)] = e.Value
+		return
+	case *sdl.ControllerDeviceEvent:
+		if e.Type == sdl.CONTROLLERDEVICEREMOVED {
+			clear(m.axes)
+		}
+		return
+	}
+	e, ok := event.(*sdl.KeyboardEvent)
+	if !ok {
+		return
+	}
+	switch e.Type {
+	case sdl.KEYDOWN:
+		m.down[e.Keysym.Scancode] = true
+		if e.Repeat == 0 {
+			m.justPressed = append(m.justPressed, e.Keysym)
+		}
+	case sdl.KEYUP:
+		delete(m.down, e.Keysym.Scancode)
+	}
+}
+
+// EndFrame forgets this frame's presses. Call it once per frame after
+// everything interested in JustPressed has run.
+func (m *InputManager) EndFrame() {
+	m.justPressed = m.justPressed[:0]
+}
+
+func (m *InputManager) Down(key sdl.Scancode) bool {
+	return m.down[key]
+}
+
+// Mouse returns the mouse position from the last motion event, in the
+// renderer's logical coordinates, which SDL translates mouse events to.
+func (m *InputManager) Mouse() (x, y int32) {
+	return m.mouse.X, m.mouse.Y
+}
+
+// Axis returns the last position of a controller axis, from -32768 to
+// 32767. With several controllers it is whichever moved last.
+func (m *InputManager) Axis(axis sdl.GameControllerAxis) int16 {
+	return m.axes[axis]
+}
+
+// JustPressed returns the keys pressed since the last EndFrame, in order,
+// not counting key repeat.
+func (m *InputManager) JustPressed() []sdl.Keysym {
+	return m.justPressed
+}
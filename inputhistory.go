@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	inputBoxPadding = 6
+	inputBoxSpacing = 4
+	inputMargin     = 10
+)
+
+type inputEntry struct {
+	label string
+	at    uint32
+}
+
+// inputHistory is the on-screen list of recent key presses, oldest first.
+type inputHistory struct {
+	entries []inputEntry
+}
+
+// recordInputs adds the keys pressed this frame and drops entries that are
+// too old or beyond the configured number shown.
+func (g *Game) recordInputs() {
+	h := g.inputs
+	now := sdl.GetTicks()
+	for _, key := range g.input.JustPressed() {
+		h.entries = append(h.entries, inputEntry{label: keyLabel(key), at: now})
+	}
+	maxAge := uint32(g.cfg.InputDisplaySeconds * 1000)
+	keep := 0
+	for keep < len(h.entries) && now-h.entries[keep].at >= maxAge {
+		keep++
+	}
+	h.entries = h.entries[keep:]
+	if n := len(h.entries) - g.cfg.InputDisplayMax; n > 0 {
+		h.entries = h.entries[n:]
+	}
+}
+
+// renderInputs draws the recent presses as labeled boxes along the bottom
+// left of the window, fading out as they age.
+func (g *Game) renderInputs() {
+	font := g.font(fontUI)
+	if font == nil {
+		return
+	}
+	now := sdl.GetTicks()
+	maxAge := float64(g.cfg.InputDisplaySeconds * 1000)
+	h := g.lineHeight(fontUI) + 2*inputBoxPadding
+	x := int32(inputMargin)
+	y := g.height - inputMargin - h
+	for _, e := range g.inputs.entries {
+		w, _, err := font.SizeUTF8(e.label)
+		if err != nil {
+			continue
+		}
+		fade := 1 - float64(now-e.at)/maxAge
+		if fade <= 0 {
+			continue
+		}
+		box := sdl.Rect{X: x, Y: y, W: int32(w) + 2*inputBoxPadding, H: h}
+		drawRoundedRect(g.renderer, box, g.cfg.PanelRadius, sdl.Color{A: uint8(180 * fade)})
+		g.drawText(fontUI, e.label, sdl.Color{R: 255, G: 255, B: 255, A: uint8(255 * fade)}, x+inputBoxPadding, y+inputBoxPadding)
+		x += box.W + inputBoxSpacing
+	}
+}
+
+// keyLabel names a key press the way it is written in the README, e.g.
+// "Shift+F12".
+func keyLabel(key sdl.Keysym) string {
+	name := sdl.GetKeyName(key.Sym)
+	if isModifierKey(key.Sym) {
+		return name
+	}
+	var mods []string
+	if key.Mod&sdl.KMOD_CTRL != 0 {
+		mods = append(mods, "Ctrl")
+	}
+	if key.Mod&sdl.KMOD_ALT != 0 {
+		mods = append(mods, "Alt")
+	}
+	if key.Mod&sdl.KMOD_SHIFT != 0 {
+		mods = append(mods, "Shift")
+	}
+	return strings.Join(append(mods, name), "+")
+}
+
+func isModifierKey(key sdl.Keycode) bool {
+	switch key {
+	case sdl.K_LCTRL, sdl.K_RCTRL, sdl.K_LALT, sdl.K_RALT, sdl.K_LSHIFT, sdl.K_RSHIFT, sdl.K_LGUI, sdl.K_RGUI:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package main
+
+const introSeconds = 1.2
+
+// startIntro slides the player in from the left and fades the title in.
+// Gameplay waits until both are done.
+func (g *Game) startIntro() {
+	g.intro = true
+	endX := g.player.box.X
+	startX := -g.player.box.W
+	g.player.box.X = startX
+	g.textAlpha = 0
+
+	slide := NewTween(introSeconds, easeOutCubic, func(p float64) {
+		g.player.box.X = lerp(startX, endX, p)
+	})
+	fade := NewTween(introSeconds, easeLinear, func(p float64) {
+		g.textAlpha = uint8(lerp(0, 255, p))
+	}).OnDone(func() { g.intro = false })
+	g.introTweens = []*Tween{slide, fade}
+	g.tweens = append(g.tweens, slide, fade)
+}
+
+// skipIntro jumps to the end of the intro.
+func (g *Game) skipIntro() {
+	for _, t := range g.introTweens {
+		t.Finish()
+	}
+	g.introTweens = nil
+	g.intro = false
+}
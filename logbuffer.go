@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const logBufferLines = 200
+
+// LogBuffer is an io.Writer that forwards everything to out and keeps the
+// last lines written in memory so they can be shown in-game.
+type LogBuffer struct {
+	mu      sync.Mutex
+	out     io.Writer
+	lines   []string
+	next    int
+	full    bool
+	partial []byte
+}
+
+func NewLogBuffer(out io.Writer, size int) *LogBuffer {
+	return &LogBuffer{out: out, lines: make([]string, size)}
+}
+
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.partial = append(b.partial, p...)
+	for {
+		i := bytes.IndexByte(b.partial, '\n')
+		if i < 0 {
+			break
+		}
+		b.add(string(b.partial[:i]))
+		b.partial = b.partial[i+1:]
+	}
+	return b.out.Write(p)
+}
+
+func (b *LogBuffer) add(line string) {
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % len(b.lines)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Lines returns a copy of the buffered lines, oldest first.
+func (b *LogBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return append([]string(nil), b.lines[:b.next]...)
+	}
+	return append(append([]string(nil), b.lines[b.next:]...), b.lines[:b.next]...)
+}
+
+// logLevelOf extracts the level a line was logged at by the text handler.
+func logLevelOf(line string) slog.Level {
+	switch {
+	case strings.Contains(line, "level=DEBUG"):
+		return slog.LevelDebug
+	case strings.Contains(line, "level=WARN"):
+		return slog.LevelWarn
+	case strings.Contains(line, "level=ERROR"):
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+// logLevel is the minimum level logged, adjustable while running.
+var logLevel = new(slog.LevelVar)
+
+func newLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: logLevel,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				a.Value = slog.StringValue(a.Value.Time().Format(time.TimeOnly))
+			}
+			return a
+		},
+	}))
+}
@@ -1,8 +1,15 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log/slog"
+	"math"
 	"math/rand"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/veandco/go-sdl2/img"
@@ -12,15 +19,31 @@ import (
 )
 
 const (
-	windowWidth  = 800
-	windowHeight = 600
 	windowTitle  = "SDL2 in Go"
 	spriteHeight = 128
 	spriteWidth  = 128
+
+	// colorCycleSeconds is how often colorCycling changes the background.
+	colorCycleSeconds = 1.0
+
+	// inputPollDelay is how long in ms the loop sleeps between polls while
+	// it waits for the next frame with decoupleInput on.
+	inputPollDelay = 1
+
+	soundCooldown  = 250 * time.Millisecond
+	bounceCooldown = 100 * time.Millisecond
+
+	// overloadWarnInterval is how often at most advance warns that it is
+	// dropping time.
+	overloadWarnInterval = 5 * time.Second
 )
 
-func initSDL() error {
-	err := sdl.Init(sdl.INIT_EVERYTHING)
+func initSDL(opts *Options) error {
+	var flags uint32 = sdl.INIT_EVERYTHING
+	if opts.NoAudio {
+		flags &^= sdl.INIT_AUDIO
+	}
+	err := sdl.Init(flags)
 	if err != nil {
 		return fmt.Errorf("Error initializing SDL2: %v", err)
 	}
@@ -32,6 +55,9 @@ func initSDL() error {
 	if err != nil {
 		return fmt.Errorf("Error initializing SDL_ttf: %v", err)
 	}
+	if opts.NoAudio {
+		return nil
+	}
 	err = mix.Init(mix.INIT_OGG)
 	if err != nil {
 		return fmt.Errorf("Error initializing SDL_mixer: %v", err)
@@ -48,107 +74,325 @@ func closeSDL() {
 }
 
 type Game struct {
+	cfg            *Config
+	opts           *Options
 	window         *sdl.Window
+	width, height  int32 // window size, in the coordinates things are drawn at
+	isFullscreen   bool
+	alwaysOnTop    bool
 	renderer       *sdl.Renderer
-	background     *sdl.Texture
-	icon           *sdl.Surface
-	fontSize       int
-	fontColor      *sdl.Color
+	assets         *AssetManager
+	bundle         *Bundle      // where assets are read from first, if given with -bundle
+	background     *sdl.Texture // owned by assets, like sprite
+	fontScale      float64      // font sizes are multiplied by this
+	display        int          // the display the window is on
+	vsync          vsyncProbe
+	pace           func(frameStart uint64) // waits out a frame as pacingMode says
+	missingFonts   map[string]bool
 	text           *sdl.Texture
 	textRect       *sdl.Rect
-	textVelocity   int
+	textPos        Vec2
 	textXVelocity  int
 	textYVelocity  int
 	sprite         *sdl.Texture
-	spriteRect     *sdl.Rect
+	spriteAnim     *Animation // the frames of the sprite image
+	sprites        []*Sprite
+	player         *Sprite
 	spriteVelocity int
-	chunkGo        *mix.Chunk
-	chunkSDL       *mix.Chunk
+	targetFPS      int
+	soundLimiter   *RateLimiter
+	bounceLimiter  *RateLimiter
+	playlist       []*mix.Music   // owned by assets
+	loopedTracks   []*loopedTrack // for each track of the playlist, nil unless it has a loop region
+	looping        *loopedTrack   // the track playing, if it has a loop region
+	introDone      atomic.Bool    // set from the audio thread once musicChannel stops
+	bounceVariants []*mix.Chunk   // the bounce sound at other pitches, owned by assets
+	loadErrors     []error        // assets that failed to load in Init
 	music          *mix.Music
+	track          int
+	channelStarts  []uint32 // SDL ticks each mixer channel last started a sound
+	logs           *LogBuffer
+	tintAnimation  bool
+	jitter         bool
+	rng            *rand.Rand
+	screenshots    []screenshotRequest
+	watcher        *configWatcher
+	menu           *optionsMenu
+	input          *InputManager
+	controllers    map[sdl.JoystickID]*sdl.GameController
+	inputs         *inputHistory
+	showInputs     bool
+	intro          bool
+	bpmPulse       bool
+	afterimage     bool
+	accum          *sdl.Texture // the scene as drawn with afterimages
+	effect         *sdl.Texture // the overlay effect at the window size
+	effectName     string       // which overlay effect is in effect
+	textTrail      bool
+	textGhosts     Trail // where the title was drawn recently
+	particles      *ParticleSystem
+	textSquash     Squash
+	playerSquash   Squash
+	introTweens    []*Tween
+	tweens         []*Tween
+	textAlpha      uint8
+	splitScreen    bool
+	freeCamera     bool   // the movement input pans freeCam instead of moving the sprite
+	freeCam        Camera // the first viewport's camera while freeCamera is on
+	hudBlur        bool
+	blur           *blurCache
+	preset         string
+	baseConfig     *Config // the settings from the config file and command line, for resetSettings
+	frameErr       error   // the first rendering error of this frame
+	renderFailures int     // frames in a row that failed to draw
+	musicHeld      bool
+	volume         int
+	muted          bool
+	mutedVolume    int    // volume to go back to when unmuted
+	volumeShown    uint32 // when the volume last changed, to show it
+	showConsole    bool
+	consoleScroll  int
+	showDebug      bool
+	clipScene      bool // draw only part of the scene, see sceneClip
+	showFPS        bool
+	overlays       []overlay // drawn over the scene in priority order
+	focusMode      bool      // hide the overlays but those in focusModeKeeps
+	state          GameState
+	menuState      *MenuState
+	playing        *PlayingState
+	windows        []*gameWindow // besides the main one
+	debugWindow    *gameWindow
+	paused         bool
+	pausedByFocus  bool // paused by focusLost rather than the player
+	confirmingQuit bool
+	quitAskedAt    uint32
+	lastInput      uint32 // SDL ticks of the last key, mouse or controller input
+	stepping       bool
+	quit           bool
+	frameCount     uint64
+	timings        frameTimings
+	frameTimes     *frameTimeLog // nil unless -frametime-log is given
+	startTime      time.Time
+
+	bouncePredictedX bool
+	bouncePredictedY bool
+	textTouching     bool // the title is touching the player, see bounceOffPlayer
+
+	accumulator     float64      // seconds not yet run in fixedTimestep updates
+	overloadLimiter *RateLimiter // limits how often advance warns about dropping time
+
+	colorCycling bool
+	colorTimer   float64   // seconds since the background color last changed
+	bgColor      sdl.Color // the background color, see backgroundColor
+
+	flocking  bool
+	grid      *spatialGrid
+	neighbors []*Sprite
+	steering  []Vec2
 }
 
-func NewGame() *Game {
-	g := Game{}
-	err := g.Init()
-	if err != nil {
-		panic(err)
+// NewGame makes and initializes a game. If that fails, whatever Init got to
+// is closed again.
+func NewGame(cfg *Config, opts *Options, logs *LogBuffer) (*Game, error) {
+	g := &Game{cfg: cfg, opts: opts, logs: logs}
+	if err := g.Init(); err != nil {
+		g.Close()
+		return nil, err
 	}
-	return &g
+	return g, nil
 }
 
 func (g *Game) Init() error {
 	var err error
 
-	g.fontSize = 80
-	g.fontColor = &sdl.Color{R: 255, G: 255, B: 255, A: 255}
-	g.spriteVelocity = 10
-	g.textVelocity = 2
-	g.textXVelocity = g.textVelocity
-	g.textYVelocity = g.textVelocity
+	g.missingFonts = make(map[string]bool)
+	base := *g.cfg
+	g.baseConfig = &base
+	g.textAlpha = 255
+	g.bgColor = sdl.Color{A: 255}
+	g.spriteVelocity = g.cfg.SpriteVelocity
+	g.targetFPS = g.cfg.TargetFPS
+	g.volume = g.cfg.Volume
+	g.textXVelocity = g.cfg.TextVelocity
+	g.textYVelocity = g.cfg.TextVelocity
+	g.tintAnimation = g.cfg.TintAnimation
+	g.jitter = g.cfg.SpriteJitter
+	g.flocking = g.cfg.Flocking
+	g.showInputs = g.cfg.ShowInputs
+	g.splitScreen = g.cfg.SplitScreen
+	g.hudBlur = g.cfg.HudBlur
+	g.bpmPulse = g.cfg.BPMPulse
+	g.afterimage = g.cfg.Afterimage
+	g.textTrail = g.cfg.TextTrail
+	g.focusMode = g.cfg.FocusMode
+	g.blur = &blurCache{textures: make(map[blurKey]*sdl.Texture)}
+	g.menuState = &MenuState{g: g}
+	g.playing = &PlayingState{g: g}
+	g.state = g.playing
+	if g.cfg.TitleScreen && g.opts.BenchFrames == 0 {
+		g.state = g.menuState
+	}
+	seed := g.cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	g.rng = rand.New(rand.NewSource(seed))
+	slog.Debug("random seed", "seed", seed)
+	g.soundLimiter = NewRateLimiter(soundCooldown)
+	g.bounceLimiter = NewRateLimiter(bounceCooldown)
+	g.overloadLimiter = NewRateLimiter(overloadWarnInterval)
+	g.menu = g.newOptionsMenu()
+	g.input = NewInputManager()
+	if g.opts.FrameTimeLog != "" {
+		g.frameTimes = newFrameTimeLog(g.opts.FrameTimeLog)
+	}
+	g.controllers = make(map[sdl.JoystickID]*sdl.GameController)
+	g.inputs = &inputHistory{}
+	g.registerOverlays()
+	if g.opts.WatchConfig {
+		g.watcher = newConfigWatcher(configPath)
+	}
 
-	g.window, err = sdl.CreateWindow(windowTitle, sdl.WINDOWPOS_CENTERED, sdl.WINDOWPOS_CENTERED, windowWidth, windowHeight, 0) //sdl.WINDOWEVENT_SHOWN)
+	windowFlags, err := parseWindowFlags(g.cfg.WindowFlags)
+	if err != nil {
+		return fmt.Errorf("Error parsing window flags: %v", err)
+	}
+	x, y, w, h := g.initialWindowGeometry()
+	g.window, err = sdl.CreateWindow(windowTitle, x, y, w, h, windowFlags|sdl.WINDOW_RESIZABLE)
 	if err != nil {
 		return fmt.Errorf("Error creating window: %v", err)
 	}
+	// Fullscreen and maximized windows don't open at the size asked for.
+	g.width, g.height = g.window.GetSize()
+	g.isFullscreen = windowFlags&(sdl.WINDOW_FULLSCREEN|sdl.WINDOW_FULLSCREEN_DESKTOP) != 0
+	if g.cfg.AlwaysOnTop {
+		g.setAlwaysOnTop(true)
+	}
+	if g.cfg.ShowCrosshair {
+		showCursor(false)
+	}
 
-	g.renderer, err = sdl.CreateRenderer(g.window, -1, sdl.RENDERER_ACCELERATED)
+	err = g.createRenderer()
 	if err != nil {
-		return fmt.Errorf("Error creating renderer: %v", err)
+		return err
+	}
+	g.renderer.SetLogicalSize(g.width, g.height)
+	if g.opts.Bundle != "" {
+		g.bundle, err = OpenBundle(g.opts.Bundle)
+		if err != nil {
+			return fmt.Errorf("Error opening asset bundle: %v", err)
+		}
+		slog.Info("loading assets from bundle", "path", g.opts.Bundle)
 	}
+	g.assets = NewAssetManager(g.renderer, g.bundle)
 
-	g.background, err = img.LoadTexture(g.renderer, "images/background.png")
+	// Missing assets are done without where possible and reported together
+	// at the end, rather than stopping the game.
+	g.background, err = g.assets.LoadTexture("background", ImageConfig{Path: g.backgroundPath()})
 	if err != nil {
-		return fmt.Errorf("Error loading background image: %v", err)
+		g.assetFailed(fmt.Errorf("Error loading background image: %v", err))
 	}
+	g.renderLoading()
 
-	g.icon, err = img.Load("images/Go-logo.png")
+	icon, err := g.loadIcon()
 	if err != nil {
-		return fmt.Errorf("Error loading icon image: %v", err)
+		g.assetFailed(err)
+	} else {
+		g.window.SetIcon(icon)
 	}
-	g.window.SetIcon(g.icon)
 
-	font, err := ttf.OpenFont("fonts/freesansbold.ttf", g.fontSize)
+	g.display, err = g.window.GetDisplayIndex()
 	if err != nil {
-		return fmt.Errorf("Error loading font: %v", err)
+		return fmt.Errorf("Error getting window display: %v", err)
 	}
-	textSurface, err := font.RenderUTF8Blended(windowTitle, *g.fontColor)
-	if err != nil {
-		return fmt.Errorf("Error creating font surface: %v", err)
+	if g.cfg.VSyncProbeFrames > 0 {
+		g.probeVSync(g.cfg.VSyncProbeFrames)
 	}
-	defer textSurface.Free()
-
-	g.text, err = g.renderer.CreateTextureFromSurface(textSurface)
-	if err != nil {
-		return fmt.Errorf("Error creating font texture: %v", err)
+	g.pace = g.pacer()
+	g.fontScale = 1
+	if g.cfg.DPIScaleFonts {
+		g.fontScale = displayFontScale(g.display)
 	}
-	g.textRect = &sdl.Rect{X: (windowWidth - textSurface.W) / 2, Y: (windowHeight - textSurface.H) / 2, W: textSurface.W, H: textSurface.H}
-
-	g.sprite, err = img.LoadTexture(g.renderer, "images/Go-logo.png")
+	err = g.loadFonts()
 	if err != nil {
-		return fmt.Errorf("Error loading sprite image: %v", err)
+		g.assetFailed(err)
 	}
-	g.spriteRect = &sdl.Rect{X: 0, Y: 0, W: spriteWidth, H: spriteHeight}
-
-	err = mix.OpenAudio(mix.DEFAULT_FREQUENCY, mix.DEFAULT_FORMAT, mix.DEFAULT_CHANNELS, mix.DEFAULT_CHUNKSIZE)
+	g.renderLoading()
+	err = g.renderTitle()
 	if err != nil {
-		return fmt.Errorf("Error initializing SDL_mixer audio: %v", err)
+		return err
 	}
+	g.textRect.X = (g.width - g.textRect.W) / 2
+	g.textRect.Y = (g.height - g.textRect.H) / 2
+	g.textPos = Vec2{X: float64(g.textRect.X), Y: float64(g.textRect.Y)}
 
-	g.chunkGo, err = mix.LoadWAV("sounds/Go.ogg")
+	g.sprite, err = g.assets.LoadTexture("sprite", g.cfg.SpriteImage)
 	if err != nil {
-		return fmt.Errorf("Error loading sound chunk: %v", err)
+		g.assetFailed(fmt.Errorf("Error loading sprite image: %v", err))
+		g.sprite, err = g.assets.SolidTexture("sprite", spriteWidth, spriteHeight, placeholderColor)
+		if err != nil {
+			return fmt.Errorf("Error creating placeholder sprite: %v", err)
+		}
 	}
-
-	g.chunkSDL, err = mix.LoadWAV("sounds/SDL.ogg")
+	g.spriteAnim, err = NewAnimation(g.sprite, g.cfg.SpriteFrames, g.cfg.SpriteFPS)
 	if err != nil {
-		return fmt.Errorf("Error loading sound chunk: %v", err)
+		g.assetFailed(fmt.Errorf("Error splitting sprite sheet: %v", err))
+		g.spriteAnim, err = NewAnimation(g.sprite, 1, 0)
+		if err != nil {
+			return fmt.Errorf("Error querying sprite image: %v", err)
+		}
 	}
+	g.renderLoading()
+	g.particles = NewParticleSystem(g.cfg.MaxParticles, g.rng)
+	g.textSquash, g.playerSquash = newSquash(), newSquash()
+	g.player = &Sprite{texture: g.sprite, box: AABB{W: spriteWidth, H: spriteHeight}, tint: untinted, collisionLayer: collideAll, collisionMask: collideAll}
+	g.sprites = []*Sprite{g.player}
 
-	g.music, err = mix.LoadMUS("music/freesoftwaresong-8bit.ogg")
-	if err != nil {
-		return fmt.Errorf("Error loading music: %v", err)
+	if !g.opts.NoAudio {
+		err = g.openAudio()
+		if err != nil {
+			return err
+		}
+		g.allocateChannels(g.cfg.SoundChannels)
+		mix.ChannelFinished(g.channelFinished)
+		g.setVolume(g.cfg.Volume)
+		if g.opts.Mute {
+			g.toggleMute()
+		}
+		g.renderLoading()
+		g.loadAudio()
 	}
 
+	g.reportLoadErrors()
+	return nil
+}
+
+// renderTitle (re)creates the title texture in the configured text color and
+// shadow, keeping the current position. Without any font there is no title
+// and nothing is drawn for it.
+func (g *Game) renderTitle() error {
+	var text *sdl.Texture
+	var w, h int32
+	if g.font(fontTitle) != nil {
+		var err error
+		text, err = g.renderTextWithShadow(fontTitle, windowTitle, g.cfg.TextColor, g.cfg.ShadowColor, g.cfg.ShadowOffsetX, g.cfg.ShadowOffsetY)
+		if err != nil {
+			return err
+		}
+		_, _, w, h, err = text.Query()
+		if err != nil {
+			text.Destroy()
+			return fmt.Errorf("Error querying font texture: %v", err)
+		}
+	}
+	if g.text != nil {
+		g.text.Destroy()
+	}
+	g.text = text
+	if g.textRect == nil {
+		g.textRect = &sdl.Rect{}
+	}
+	g.textRect.W, g.textRect.H = w, h
 	return nil
 }
 
@@ -157,96 +401,555 @@ func (g *Game) Close() {
 		return
 	}
 
-	mix.HaltMusic()
-	mix.HaltChannel(-1)
-
-	if g.window != nil {
-		g.window.Destroy()
-	}
-	if g.renderer != nil {
-		g.renderer.Destroy()
+	if !g.opts.NoAudio {
+		// Freeing music that is still fading out would cut it off.
+		g.waitMusicFade()
+		mix.HaltMusic()
+		g.stopLooped()
+		mix.HaltChannel(-1)
 	}
 
-	if g.background != nil {
-		g.background.Destroy()
-	}
-	if g.icon != nil {
-		g.icon.Free()
+	g.closeWindows()
+	if g.blur != nil {
+		g.blur.clear()
 	}
+	g.freeAfterimage()
+	g.freeEffect()
 	if g.textRect != nil {
 		g.textRect = nil
 	}
 	if g.text != nil {
 		g.text.Destroy()
 	}
-	if g.sprite != nil {
-		g.sprite.Destroy()
+	g.playlist = nil
+	g.loopedTracks = nil
+	g.bounceVariants = nil
+	g.music = nil
+	if g.assets != nil {
+		g.assets.Destroy()
 	}
-	if g.chunkGo != nil {
-		g.chunkGo.Free()
+	if g.bundle != nil {
+		g.bundle.Close()
 	}
-	if g.chunkSDL != nil {
-		g.chunkSDL.Free()
+	g.closeControllers()
+
+	if g.renderer != nil {
+		g.renderer.Destroy()
 	}
-	if g.music != nil {
-		g.music.Free()
+	if g.window != nil {
+		if g.cfg.RememberWindow {
+			if err := g.saveWindowState(); err != nil {
+				slog.Warn("could not save the window position", "err", err)
+			}
+		}
+		g.window.Destroy()
 	}
 }
 
-func (g *Game) Run() {
-	g.music.Play(-1)
+// Why Run returned.
+const (
+	exitQuit   = "quit"   // the player, the window or -run-for asked to quit
+	exitBench  = "bench"  // a -bench-frames run drew all its frames
+	exitSignal = "signal" // a signal arrived on the stop channel
+)
 
-	fmt.Printf("%+v\n", g.spriteRect)
+// Run runs the game until it is asked to quit or a signal arrives on stop,
+// returning why, or until frames keep failing to draw, returning the error.
+// Fading the music out and closing the game are left to the caller.
+func (g *Game) Run(stop <-chan os.Signal) (string, error) {
+	g.playTrack(0, musicFadeInMillis)
+	if g.cfg.IntroAnimation {
+		g.startIntro()
+	}
 
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
+	slog.Info("game started")
+	slog.Debug("sprite start", "box", g.player.box)
 
-	go func(ticker *time.Ticker) {
-		for range ticker.C {
-			g.randColor()
-		}
-	}(ticker)
+	g.setColorCycling(g.cfg.ColorCycling)
+	g.setDebugWindow(g.cfg.DebugWindow)
+	if g.opts.BenchFrames > 0 {
+		g.startBench()
+	}
+	defer g.writeFrameTimes()
+
+	g.startTime = time.Now()
+	start := sdl.GetTicks()
+	quitPushed := false
+	last := time.Now()
+	nextFrame := last
 
 	for {
+		frameStart := sdl.GetPerformanceCounter()
+		if g.opts.RunFor > 0 && !quitPushed && sdl.GetTicks()-start >= uint32(g.opts.RunFor*1000) {
+			slog.Info("run time elapsed, quitting", "seconds", g.opts.RunFor)
+			sdl.PushEvent(&sdl.QuitEvent{Type: sdl.QUIT})
+			quitPushed = true
+		}
+		select {
+		case sig := <-stop:
+			slog.Info("signal received, quitting", "signal", sig)
+			return exitSignal, nil
+		default:
+		}
+
+		if g.watcher != nil && g.watcher.changed() {
+			g.reloadConfig()
+		}
+
 		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+			g.handleEvent(event)
+			if g.quit {
+				return exitQuit, nil
+			}
+		}
+
+		now := time.Now()
+		t0 := sdl.GetPerformanceCounter()
+		g.advance(now.Sub(last).Seconds())
+		last = now
+		g.timings.update.Add(perfMillis(t0, sdl.GetPerformanceCounter()))
+
+		if g.opts.BenchFrames > 0 {
+			if err := g.presentFrame(); err != nil {
+				return "", err
+			}
+			g.fillBenchParticles()
+			if g.benchDone() {
+				return exitBench, nil
+			}
+			continue
+		}
+		if !g.cfg.DecoupleInput {
+			if err := g.presentFrame(); err != nil {
+				return "", err
+			}
+			g.pace(frameStart)
+			continue
+		}
+		period := time.Second / time.Duration(g.targetFPS)
+		// Only render when the next frame is due, keeping to the schedule
+		// unless a frame ran so late it would have to catch up.
+		if now.Before(nextFrame) {
+			sdl.Delay(inputPollDelay)
+			continue
+		}
+		if err := g.presentFrame(); err != nil {
+			return "", err
+		}
+		nextFrame = nextFrame.Add(period)
+		if nextFrame.Before(now) {
+			nextFrame = now.Add(period)
+		}
+	}
+}
+
+// presentFrame renders and presents a frame, timing both. It returns an
+// error once frames have failed to draw renderFailureLimit times in a row.
+func (g *Game) presentFrame() error {
+	t0 := sdl.GetPerformanceCounter()
+	g.render()
+	t1 := sdl.GetPerformanceCounter()
+	g.renderer.Present()
+	t2 := sdl.GetPerformanceCounter()
+	g.presentWindows()
+	g.frameCount++
+	if g.frameTimes != nil {
+		g.frameTimes.Presented(t2)
+	}
+
+	g.timings.render.Add(perfMillis(t0, t1))
+	g.timings.present.Add(perfMillis(t1, t2))
+	if g.timings.lastPresent != 0 {
+		g.timings.frame.Add(perfMillis(g.timings.lastPresent, t2))
+	}
+	g.timings.lastPresent = t2
+	return g.frameFailed()
+}
+
+// InjectEvent feeds a synthetic event through the same handler as the
+// events polled from SDL, e.g. to drive the game from a test.
+func (g *Game) InjectEvent(event sdl.Event) {
+	g.handleEvent(event)
+}
+
+// handleEvent handles what every state has in common, such as window events
+// and the options menu and quit question, passing anything else on to the
+// current state.
+func (g *Game) handleEvent(event sdl.Event) {
+	g.input.Handle(event)
+	g.noteInput(event)
+	switch e := event.(type) {
+	case *sdl.QuitEvent:
+		g.quit = true
+	case *sdl.ControllerDeviceEvent:
+		g.handleControllerDevice(e)
+	case *sdl.WindowEvent:
+		if w := g.extraWindow(e.WindowID); w != nil {
+			g.handleWindowEvent(w, e)
+			return
+		}
+		switch e.Event {
+		case sdl.WINDOWEVENT_CLOSE:
+			// SDL only sends a quit event once the last window closes.
+			g.quit = true
+		case sdl.WINDOWEVENT_MOVED, sdl.WINDOWEVENT_DISPLAY_CHANGED:
+			g.updateDisplay()
+		case sdl.WINDOWEVENT_FOCUS_LOST:
+			g.focusLost()
+		case sdl.WINDOWEVENT_FOCUS_GAINED:
+			g.focusGained()
+		case sdl.WINDOWEVENT_SIZE_CHANGED:
+			// Sent for every size change, unlike WINDOWEVENT_RESIZED
+			// which leaves out those the game made itself.
+			g.resize(e.Data1, e.Data2)
+		}
+	case *sdl.KeyboardEvent:
+		if g.menu.open && e.Type == sdl.KEYDOWN && g.handleMenuKey(e.Keysym.Sym) {
+			return
+		}
+		if g.confirmingQuit {
+			if e.Type == sdl.KEYDOWN {
+				g.handleQuitConfirmKey(e.Keysym.Sym)
+			}
+			return
+		}
+		if e.Type == sdl.KEYDOWN && (e.Keysym.Sym == sdl.K_F11 || e.Keysym.Sym == sdl.K_RETURN && e.Keysym.Mod&sdl.KMOD_ALT != 0) {
+			g.toggleFullscreen()
+			return
+		}
+		g.state.HandleEvent(event)
+	default:
+		g.state.HandleEvent(event)
+	}
+}
+
+// handlePlayingEvent handles the controls of the game while playing.
+func (g *Game) handlePlayingEvent(event sdl.Event) {
+	if e, ok := event.(*sdl.KeyboardEvent); ok && g.intro && e.Type == sdl.KEYDOWN {
+		g.skipIntro()
+		return
+	}
+	switch e := event.(type) {
+	case *sdl.ControllerButtonEvent:
+		g.handleControllerButton(e)
+	case *sdl.MouseButtonEvent:
+		g.handleMouseButton(e)
+	case *sdl.KeyboardEvent:
+		// With a title screen, the Escape that would quit goes back to it
+		// instead, to be quit from there.
+		if e.Keysym.Sym == sdl.K_ESCAPE && e.Type == sdl.KEYDOWN && g.cfg.EscapeQuits && g.cfg.TitleScreen {
+			g.setState(g.menuState)
+			return
+		}
+		if e.Type == sdl.KEYDOWN && g.isQuitKey(e.Keysym.Sym) {
+			g.requestQuit()
+			return
+		}
+		if e.Keysym.Sym == sdl.K_ESCAPE && e.Type == sdl.KEYDOWN {
+			g.closeOverlays()
+		}
+		if e.Keysym.Sym == sdl.K_SPACE && e.Type == sdl.KEYDOWN {
+			g.doAction()
+		}
+		if e.Keysym.Sym == sdl.K_c && e.Type == sdl.KEYDOWN {
+			g.setColorCycling(!g.colorCycling)
+		}
+		if e.Keysym.Sym == sdl.K_m && e.Type == sdl.KEYDOWN {
+			g.toggleMute()
+		}
+		if e.Type == sdl.KEYDOWN {
+			switch e.Keysym.Sym {
+			case sdl.K_EQUALS, sdl.K_PLUS, sdl.K_KP_PLUS:
+				g.changeVolume(volumeStep)
+			case sdl.K_MINUS, sdl.K_KP_MINUS:
+				g.changeVolume(-volumeStep)
+			}
+		}
+		if e.Keysym.Sym == sdl.K_F12 && e.Type == sdl.KEYDOWN {
+			if e.Keysym.Mod&sdl.KMOD_SHIFT != 0 {
+				g.queueScreenshot(g.worldToScreen(g.player.box), "sprite")
+			} else {
+				g.queueScreenshot(sdl.Rect{}, "screenshot")
+			}
+		}
+		if e.Keysym.Sym == sdl.K_t && e.Type == sdl.KEYDOWN {
+			if e.Keysym.Mod&sdl.KMOD_CTRL != 0 {
+				g.setAlwaysOnTop(!g.alwaysOnTop)
+			} else {
+				g.tintAnimation = !g.tintAnimation
+			}
+		}
+		if e.Type == sdl.KEYDOWN && e.Keysym.Sym >= sdl.K_1 && e.Keysym.Sym <= sdl.K_3 {
+			delta := tintStep
+			if e.Keysym.Mod&sdl.KMOD_SHIFT != 0 {
+				delta = -tintStep
+			}
+			g.player.adjustTint(int(e.Keysym.Sym-sdl.K_1), delta)
+		}
+		if e.Keysym.Sym == sdl.K_0 && e.Type == sdl.KEYDOWN {
+			g.player.tint = untinted
+		}
+		if e.Type == sdl.KEYDOWN && g.cfg.ResetKey != "" && e.Keysym.Sym == sdl.GetKeyFromName(g.cfg.ResetKey) {
+			g.resetSettings()
+		}
+		if e.Keysym.Sym == sdl.K_F2 && e.Type == sdl.KEYDOWN {
+			g.toggleFocusMode()
+		}
+		if e.Keysym.Sym == sdl.K_F3 && e.Type == sdl.KEYDOWN {
+			g.showFPS = !g.showFPS
+		}
+		if e.Keysym.Sym == sdl.K_F4 && e.Type == sdl.KEYDOWN {
+			g.showDebug = !g.showDebug
+		}
+		if e.Keysym.Sym == sdl.K_F7 && e.Type == sdl.KEYDOWN {
+			g.clipScene = !g.clipScene
+		}
+		if e.Keysym.Sym == sdl.K_F10 && e.Type == sdl.KEYDOWN {
+			g.setDebugWindow(g.debugWindow == nil)
+		}
+		if e.Keysym.Sym == sdl.K_F5 && e.Type == sdl.KEYDOWN {
+			g.splitScreen = !g.splitScreen
+		}
+		if e.Keysym.Sym == sdl.K_l && e.Type == sdl.KEYDOWN {
+			g.toggleFreeCamera()
+		}
+		if e.Keysym.Sym == sdl.K_p && e.Type == sdl.KEYDOWN {
+			g.togglePause()
+		}
+		if e.Keysym.Sym == sdl.K_PERIOD && e.Type == sdl.KEYDOWN {
+			g.stepFrame()
+		}
+		if e.Keysym.Sym == sdl.K_F9 && e.Type == sdl.KEYDOWN {
+			g.writeFrameTimes()
+		}
+		if e.Keysym.Sym == sdl.K_F8 && e.Type == sdl.KEYDOWN {
+			g.logAssets()
+		}
+		if e.Keysym.Sym == sdl.K_TAB && e.Type == sdl.KEYDOWN {
+			g.spawnGroup()
+		}
+		if e.Keysym.Sym == sdl.K_i && e.Type == sdl.KEYDOWN {
+			g.showInputs = !g.showInputs
+		}
+		if e.Keysym.Sym == sdl.K_o && e.Type == sdl.KEYDOWN {
+			g.toggleMenu()
+		}
+		if e.Keysym.Sym == sdl.K_BACKQUOTE && e.Type == sdl.KEYDOWN {
+			g.showConsole = !g.showConsole
+			g.consoleScroll = 0
+		}
+		if g.showConsole && e.Type == sdl.KEYDOWN {
+			g.scrollConsole(e.Keysym.Sym)
+		}
+	}
+}
+
+// Tick advances the game by dt seconds without rendering anything.
+func (g *Game) Tick(dt float64) {
+	g.recordInputs()
+	defer g.input.EndFrame()
+	g.updateMusic()
+	g.updateQuitConfirm()
+	g.updateColorCycling(dt)
+	if g.confirmingQuit {
+		return
+	}
+	g.state.Update(dt)
+}
+
+// update moves the game on by dt seconds while playing.
+func (g *Game) update(dt float64) {
+	// A paused game only moves when stepped, and then by one frame at the
+	// target frame rate as it would while running.
+	if g.paused {
+		if !g.stepping {
+			return
+		}
+		g.stepping = false
+		dt = 1 / float64(g.targetFPS)
+	}
+	g.updateTweens(dt)
+	g.spriteAnim.Update(dt)
+	if g.intro {
+		return
+	}
+
+	if !g.menu.open {
+		if g.freeCamera {
+			g.panCamera(dt)
+		} else {
+			g.moveSprite(dt)
+		}
+	}
+	if !g.frozen() {
+		if g.textTrail {
+			g.textGhosts.Push(g.textPos, g.cfg.TextTrailLength)
+		}
+		g.moveText(dt)
+		g.bounceOffPlayer()
+		g.updateSprites(dt)
+		g.particles.Update(dt)
+	}
+}
 
-			switch e := event.(type) {
-			case *sdl.QuitEvent:
-				return
-			case *sdl.KeyboardEvent:
-				if e.Keysym.Sym == sdl.K_ESCAPE && e.Type == sdl.KEYDOWN {
-					//fmt.Printf("Pressed %+v\n", e)
-					return
-				}
-				if e.Keysym.Sym == sdl.K_SPACE && e.Type == sdl.KEYDOWN {
-					g.chunkGo.Play(-1, 0)
-					g.randColor()
-				}
-				if e.Keysym.Sym == sdl.K_m && e.Type == sdl.KEYDOWN {
-					g.pauseUnpauseMusic()
-				}
+// renderScene draws the background, title, particles and sprites as seen through cam
+// into the current viewport.
+func (g *Game) renderScene(cam Camera) {
+	g.renderBackground(cam)
+	if g.text != nil {
+		g.text.SetAlphaMod(g.textAlpha)
+		for _, r := range g.textRects() {
+			if g.textTrail {
+				offset := Vec2{X: float64(r.X) - g.textPos.X, Y: float64(r.Y) - g.textPos.Y}
+				g.textGhosts.Draw(g.renderer, g.text, r.W, r.H, offset, cam, g.cfg.TextTrailAlpha)
 			}
+			text := cam.Apply(g.textSquash.Apply(AABBFromRect(r).FRect()))
+			g.checkRender("drawing the title", g.renderer.CopyF(g.text, nil, &text))
 		}
+	}
+	g.particles.Render(g.renderer, cam)
+	g.renderSprites(cam)
+}
+
+// backgroundColor is the color behind the scene, pulsed to the beat when
+// the BPM pulse is on.
+func (g *Game) backgroundColor() sdl.Color {
+	r, gr, b, a := g.bgColor.R, g.bgColor.G, g.bgColor.B, g.bgColor.A
+	if g.bpmPulse && !g.cfg.ReducedMotion {
+		r, gr, b = scaleColor(r, gr, b, beatBrightness(sdl.GetTicks(), g.cfg.BPM))
+	}
+	return sdl.Color{R: r, G: gr, B: b, A: a}
+}
+
+// clear fills the current render target with the background color.
+func (g *Game) clear() {
+	r, gr, b, a, _ := g.renderer.GetDrawColor()
+	defer g.renderer.SetDrawColor(r, gr, b, a)
+	c := g.backgroundColor()
+	g.checkRender("setting the clear color", g.renderer.SetDrawColor(c.R, c.G, c.B, c.A))
+	g.checkRender("clearing the frame", g.renderer.Clear())
+}
+
+// advance moves the game on by a frame time of dt seconds, clamped to
+// maxDeltaTime. With a fixedTimestep the time is added up and run in updates
+// of that many seconds each, at most maxUpdatesPerFrame of them. Time left
+// over past that is dropped, so under sustained overload the game slows
+// down instead of trying to catch up with ever more updates.
+func (g *Game) advance(dt float64) {
+	dt = g.clampDelta(dt)
+	step := g.cfg.FixedTimestep
+	if step <= 0 {
+		g.Tick(dt)
+		return
+	}
+	n, left, dropped := fixedSteps(g.accumulator+dt, step, g.cfg.MaxUpdatesPerFrame)
+	if dropped > 0 && g.overloadLimiter.Allow() {
+		slog.Warn("updates can't keep up, dropping time", "seconds", dropped, "updates", n)
+	}
+	g.accumulator = left
+	for i := 0; i < n; i++ {
+		g.Tick(step)
+	}
+}
+
+// fixedSteps splits acc seconds into updates of step seconds, at most max
+// of them, returning how many to run, the time left over for the next frame
+// and the time dropped past max. What is dropped is whole steps only, so the
+// leftover stays less than a step.
+func fixedSteps(acc, step float64, max int) (n int, left, dropped float64) {
+	n = int(acc / step)
+	left = acc - float64(n)*step
+	if n > max {
+		dropped = float64(n-max) * step
+		n = max
+	}
+	return n, left, dropped
+}
+
+// clampDelta limits a frame time to maxDeltaTime, so that after a long stall
+// such as a debugger pause things don't jump through the walls.
+func (g *Game) clampDelta(dt float64) float64 {
+	if dt > g.cfg.MaxDeltaTime {
+		slog.Debug("clamping frame time", "dt", dt, "max", g.cfg.MaxDeltaTime)
+		return g.cfg.MaxDeltaTime
+	}
+	return dt
+}
 
-		keyboard := sdl.GetKeyboardState()
-		if keyboard[sdl.SCANCODE_UP] != 0 || keyboard[sdl.SCANCODE_DOWN] != 0 || keyboard[sdl.SCANCODE_LEFT] != 0 || keyboard[sdl.SCANCODE_RIGHT] != 0 || keyboard[sdl.SCANCODE_W] != 0 || keyboard[sdl.SCANCODE_A] != 0 || keyboard[sdl.SCANCODE_S] != 0 || keyboard[sdl.SCANCODE_D] != 0 {
-			g.moveSprite(keyboard)
+// render draws the current state and the overlays over it, without
+// presenting the frame, so Run can time Present on its own.
+func (g *Game) render() {
+	g.state.Render(g.renderer)
+	g.takeScreenshots()
+	g.renderOverlays()
+}
+
+// renderPlaying draws the scene in every viewport.
+func (g *Game) renderPlaying() {
+	// g.randColor() // Uncomment to change color every frame, gives seizures
+	ghosted := g.afterimage && g.beginAfterimage()
+	if !ghosted {
+		g.freeAfterimage()
+		g.clear()
+	}
+	for _, v := range g.viewports() {
+		g.renderer.SetViewport(&v.rect)
+		if g.clipScene {
+			clip := sceneClip(v)
+			g.renderer.SetClipRect(&clip)
 		}
-		// g.randColor() // Uncomment to change color every frame, gives seizures
-		g.renderer.Clear()
-		g.renderer.Copy(g.background, nil, nil)
+		g.renderScene(v.camera)
+		g.renderer.SetClipRect(nil)
+	}
+	g.renderer.SetViewport(nil)
+	if g.splitScreen {
+		g.renderDivider()
+	}
+	if ghosted {
+		g.endAfterimage()
+	}
+}
 
-		g.moveText()
-		g.renderer.Copy(g.text, nil, g.textRect)
-		g.renderer.Copy(g.sprite, nil, g.spriteRect)
-		g.renderer.Present()
+// isQuitKey reports whether key quits the game. Escape is governed by the
+// escapeQuits setting even when it is also the configured quit key.
+func (g *Game) isQuitKey(key sdl.Keycode) bool {
+	if key == sdl.K_ESCAPE {
+		return g.cfg.EscapeQuits
+	}
+	return g.cfg.QuitKey != "" && key == sdl.GetKeyFromName(g.cfg.QuitKey)
+}
+
+// doAction plays the action sound, unless it played too recently, and
+// changes the background color.
+func (g *Game) doAction() {
+	if g.soundLimiter.Allow() {
+		g.playSound(soundAction)
+	}
+	g.randColor()
+}
 
-		sdl.Delay(20)
+func (g *Game) closeOverlays() {
+	if g.menu.open {
+		g.toggleMenu()
 	}
+	g.showConsole = false
+	g.showDebug = false
+}
+
+// playBounce plays the bounce sound for an impact at speed pixels per second,
+// x across the window, unless one was played too recently, so rapid bounces
+// don't stack up on the mixer, and reports whether it played.
+func (g *Game) playBounce(speed, x float64) bool {
+	if !g.bounceLimiter.Allow() {
+		return false
+	}
+	g.playBounceSound(speed, x)
+	return true
 }
 
 func (g *Game) pauseUnpauseMusic() {
-	if mix.PlayingMusic() {
+	g.musicHeld = false
+	if g.music != nil && mix.PlayingMusic() {
 		if mix.PausedMusic() {
 			mix.ResumeMusic()
 		} else {
@@ -255,58 +958,410 @@ func (g *Game) pauseUnpauseMusic() {
 	}
 }
 
-func (g *Game) moveSprite(keyboard []uint8) {
-	if keyboard[sdl.SCANCODE_UP] != 0 || keyboard[sdl.SCANCODE_W] != 0 {
-		if g.spriteRect.Y >= 0 && g.spriteRect.Y-int32(g.spriteVelocity) >= 0 {
-			g.spriteRect.Y -= int32(g.spriteVelocity)
+// moveSprite moves the player by dt seconds' worth of its velocity in the
+// direction of the held arrow or WASD keys.
+func (g *Game) moveSprite(dt float64) {
+	g.player.update(dt)
+	dx, dy := g.movement(float64(g.spriteVelocity) * dt)
+	if dx == 0 && dy == 0 {
+		return
+	}
+	p := &g.player.box
+	speed := math.Hypot(dx, dy) / dt
+	var bounced bool
+	if dy != 0 {
+		if p.Y, bounced = g.stepAxis(p.Y, p.H, dy, float64(g.height), g.cfg.TopBehavior, g.cfg.BottomBehavior); bounced {
+			g.playBounce(speed, p.X+p.W/2)
+			g.squash(&g.playerSquash, false)
 		}
 	}
-	if keyboard[sdl.SCANCODE_DOWN] != 0 || keyboard[sdl.SCANCODE_S] != 0 {
-		if g.spriteRect.Y+g.spriteRect.H < windowHeight && g.spriteRect.Y+g.spriteRect.H+int32(g.spriteVelocity) <= windowHeight {
-			g.spriteRect.Y += int32(g.spriteVelocity)
+	if dx != 0 {
+		if p.X, bounced = g.stepAxis(p.X, p.W, dx, float64(g.width), g.cfg.LeftBehavior, g.cfg.RightBehavior); bounced {
+			g.playBounce(speed, p.X+p.W/2)
+			g.squash(&g.playerSquash, true)
 		}
 	}
-	if keyboard[sdl.SCANCODE_LEFT] != 0 || keyboard[sdl.SCANCODE_A] != 0 {
-		if g.spriteRect.X > 0 && g.spriteRect.X-int32(g.spriteVelocity) >= 0 {
-			g.spriteRect.X -= int32(g.spriteVelocity)
+	slog.Debug("sprite moved", "box", *p)
+}
+
+// movement is how far the arrow keys, WASD and left stick move things this
+// frame on each axis, v at most.
+func (g *Game) movement(v float64) (dx, dy float64) {
+	if g.input.Down(sdl.SCANCODE_UP) || g.input.Down(sdl.SCANCODE_W) {
+		dy -= v
+	}
+	if g.input.Down(sdl.SCANCODE_DOWN) || g.input.Down(sdl.SCANCODE_S) {
+		dy += v
+	}
+	if g.input.Down(sdl.SCANCODE_LEFT) || g.input.Down(sdl.SCANCODE_A) {
+		dx -= v
+	}
+	if g.input.Down(sdl.SCANCODE_RIGHT) || g.input.Down(sdl.SCANCODE_D) {
+		dx += v
+	}
+	// The left stick adds to the keys, each axis on its own, without
+	// going faster than the keys alone.
+	mode, threshold := g.cfg.StickMode, g.cfg.StickThreshold
+	dx = max(-v, min(dx+v*stickValue(g.input.Axis(sdl.CONTROLLER_AXIS_LEFTX), mode, threshold), v))
+	dy = max(-v, min(dy+v*stickValue(g.input.Axis(sdl.CONTROLLER_AXIS_LEFTY), mode, threshold), v))
+	return dx, dy
+}
+
+// stepAxis moves pos by delta along an axis of the given length and applies
+// the edge behavior of the low (left/top) or high (right/bottom) border when
+// the sprite would cross it, reporting whether it bounced. Clamping refuses
+// the whole step, leaving the sprite where it was, as it always has.
+func (g *Game) stepAxis(pos, size, delta, length float64, low, high string) (float64, bool) {
+	next := pos + delta
+	if delta < 0 && next < 0 {
+		switch low {
+		case edgeBounce:
+			return -next, true
+		case edgeWrap:
+			if next+size <= 0 {
+				return next + length + size, false
+			}
+			return next, false
+		default:
+			return pos, false
 		}
 	}
-	if keyboard[sdl.SCANCODE_RIGHT] != 0 || keyboard[sdl.SCANCODE_D] != 0 {
-		if g.spriteRect.X+g.spriteRect.W < windowWidth && g.spriteRect.X+g.spriteRect.W+int32(g.spriteVelocity) <= windowWidth {
-			g.spriteRect.X += int32(g.spriteVelocity)
+	if delta > 0 && next+size > length {
+		switch high {
+		case edgeBounce:
+			return 2*(length-size) - next, true
+		case edgeWrap:
+			if next >= length {
+				return next - length - size, false
+			}
+			return next, false
+		default:
+			return pos, false
 		}
 	}
-	fmt.Printf("%+v\n", g.spriteRect)
+	return next, false
 }
 
-func (g *Game) moveText() {
-	g.textRect.X += int32(g.textXVelocity)
-	g.textRect.Y += int32(g.textYVelocity)
+// moveText moves the title by dt seconds' worth of its velocity, bouncing
+// it off the window edges.
+func (g *Game) moveText(dt float64) {
+	switch g.cfg.TextMode {
+	case textStatic:
+		return
+	case textMarquee:
+		g.scrollText(dt)
+		return
+	}
+	w, h := float64(g.textRect.W), float64(g.textRect.H)
+	vx, vy := float64(g.textXVelocity)*dt, float64(g.textYVelocity)*dt
+	g.textPos.X += vx
+	g.textPos.Y += vy
+	g.textRect.X, g.textRect.Y = int32(math.Round(g.textPos.X)), int32(math.Round(g.textPos.Y))
 
-	if g.textRect.X <= 0 || g.textRect.X+g.textRect.W >= windowWidth {
-		g.textXVelocity = -g.textXVelocity
-		g.chunkSDL.Play(-1, 0)
+	speed := math.Hypot(float64(g.textXVelocity), float64(g.textYVelocity))
+	left, right := g.cfg.LeftBounceSound, g.cfg.RightBounceSound
+	top, bottom := g.cfg.TopBounceSound, g.cfg.BottomBounceSound
+	if textHitsWall(g.textPos.X, w, float64(g.width)) {
+		g.textXVelocity = g.limitTextSpeed(-g.textXVelocity)
+		g.bounceParticles(true)
+		g.squash(&g.textSquash, true)
+		if wallSound(g.textPos.X, left, right) {
+			g.bounceText(&g.bouncePredictedX, speed, g.textPos.X+w/2)
+		}
 	}
-	if g.textRect.Y <= 0 || g.textRect.Y+g.textRect.H >= windowHeight {
-		g.textYVelocity = -g.textYVelocity
-		g.chunkSDL.Play(-1, 0)
+	if textHitsWall(g.textPos.Y, h, float64(g.height)) {
+		g.textYVelocity = g.limitTextSpeed(-g.textYVelocity)
+		g.bounceParticles(false)
+		g.squash(&g.textSquash, false)
+		if wallSound(g.textPos.Y, top, bottom) {
+			g.bounceText(&g.bouncePredictedY, speed, g.textPos.X+w/2)
+		}
+	}
+
+	// The mixer buffers audio, so the sound lags the bounce by about a
+	// frame. Predictive mode starts it one frame before the wall is reached.
+	if g.cfg.PredictiveBounceAudio {
+		if x := g.textPos.X + float64(g.textXVelocity)*dt; textHitsWall(x, w, float64(g.width)) && wallSound(x, left, right) && !g.bouncePredictedX {
+			g.bouncePredictedX = g.playBounce(speed, x+w/2)
+		}
+		if y := g.textPos.Y + float64(g.textYVelocity)*dt; textHitsWall(y, h, float64(g.height)) && wallSound(y, top, bottom) && !g.bouncePredictedY {
+			g.bouncePredictedY = g.playBounce(speed, g.textPos.X+w/2)
+		}
 	}
 }
 
-func (g *Game) randColor() error {
-	g.renderer.SetDrawColor(uint8(rand.Intn(256)), uint8(rand.Intn(256)), uint8(rand.Intn(256)), 0)
-	return nil
+// bounceOffPlayer bounces the title off the player sprite when they touch,
+// along the axis they overlap least on, and plays the action sound. The
+// title collides like a sprite in every group, so the player's collision
+// mask decides whether it bounces at all. Only touching again after they
+// come apart bounces it once more, so the title can't get stuck flipping
+// back and forth inside the sprite.
+func (g *Game) bounceOffPlayer() {
+	if g.cfg.TextMode != textBounce {
+		return
+	}
+	title := &Sprite{
+		box:            AABB{X: g.textPos.X, Y: g.textPos.Y, W: float64(g.textRect.W), H: float64(g.textRect.H)},
+		collisionLayer: collideAll,
+		collisionMask:  collideAll,
+	}
+	if !title.Collides(g.player) {
+		g.textTouching = false
+		return
+	}
+	if g.textTouching {
+		return
+	}
+	g.textTouching = true
+	overlap, _ := title.box.Overlap(g.player.box)
+	horizontal := overlap.W < overlap.H
+	if horizontal {
+		g.textXVelocity = g.limitTextSpeed(-g.textXVelocity)
+	} else {
+		g.textYVelocity = g.limitTextSpeed(-g.textYVelocity)
+	}
+	g.squash(&g.textSquash, horizontal)
+	g.squash(&g.playerSquash, horizontal)
+	g.playSound(soundAction)
+	slog.Debug("title bounced off the sprite", "overlap", overlap)
+}
+
+// limitTextSpeed keeps a velocity of the title between minTextSpeed and
+// maxTextSpeed, whichever way it goes.
+func (g *Game) limitTextSpeed(v int) int {
+	s := sign(v)
+	return s * min(max(s*v, g.cfg.MinTextSpeed), g.cfg.MaxTextSpeed)
+}
+
+func textHitsWall(pos, size, length float64) bool {
+	return pos <= 0 || pos+size >= length
+}
+
+// wallSound reports whether hitting a wall at pos plays the bounce sound:
+// low for the left or top wall, which pos is at or past, high otherwise.
+func wallSound(pos float64, low, high bool) bool {
+	if pos <= 0 {
+		return low
+	}
+	return high
+}
+
+// bounceText plays the bounce sound for an actual bounce at speed, x across
+// the window, unless it was already played ahead of time for this axis.
+func (g *Game) bounceText(predicted *bool, speed, x float64) {
+	if *predicted {
+		*predicted = false
+		return
+	}
+	g.playBounce(speed, x)
+}
+
+// backgroundPath is the image the background is drawn from: the tile if
+// one is configured, otherwise the full-window background.
+func (g *Game) backgroundPath() string {
+	if g.cfg.BackgroundTile != "" {
+		return g.cfg.BackgroundTile
+	}
+	return g.cfg.Background
+}
+
+func (g *Game) renderBackground(cam Camera) {
+	if g.background == nil {
+		// The image failed to load, so the clear color is the background.
+		return
+	}
+	dst := AABB{X: -cam.Pos.X, Y: -cam.Pos.Y, W: float64(g.width), H: float64(g.height)}.Rect()
+	if g.cfg.BackgroundTile != "" {
+		g.checkRender("drawing the tiled background", fillTiled(g.renderer, g.background, dst))
+		return
+	}
+	g.checkRender("drawing the background", g.renderer.Copy(g.background, nil, &dst))
+}
+
+// spriteRenderRect is where the sprite is drawn this frame: its logical
+// box plus every purely visual offset, all applied here.
+func (g *Game) spriteRenderRect() sdl.FRect {
+	r := g.playerSquash.Apply(g.player.box.FRect())
+	if g.jitter {
+		amp := g.cfg.JitterAmplitude
+		r.X += float32((g.rng.Float64()*2 - 1) * amp)
+		r.Y += float32((g.rng.Float64()*2 - 1) * amp)
+	}
+	return r
+}
+
+// spriteColorMod is the single place that decides how the sprite is tinted,
+// so effects touching its color mod compose in a fixed order: the player's
+// own tint, multiplied by the tint animation when it is on.
+func (g *Game) spriteColorMod() (r, gr, b uint8) {
+	t := g.player.tint
+	if !g.tintAnimation {
+		return t.R, t.G, t.B
+	}
+	ar, ag, ab := hsvToRGB(float64(sdl.GetTicks())/1000*g.cfg.TintSpeed, 0.6, 1)
+	mul := func(a, b uint8) uint8 { return uint8(int(a) * int(b) / 255) }
+	return mul(ar, t.R), mul(ag, t.G), mul(ab, t.B)
+}
+
+// setColorCycling starts or stops changing the background color every
+// colorCycleSeconds. Stopping leaves the color as it was.
+func (g *Game) setColorCycling(on bool) {
+	g.colorCycling = on
+	g.colorTimer = 0
+}
+
+// updateColorCycling counts dt seconds towards the next color change. A
+// long frame changes the color once, not once for every second it took.
+func (g *Game) updateColorCycling(dt float64) {
+	if !g.colorCycling {
+		return
+	}
+	g.colorTimer += dt
+	if g.colorTimer >= colorCycleSeconds {
+		g.colorTimer = math.Mod(g.colorTimer, colorCycleSeconds)
+		g.randColor()
+	}
+}
+
+// randColor picks a new background color. It is only drawn with by clear,
+// so it can be changed at any time.
+func (g *Game) randColor() {
+	g.bgColor = sdl.Color{R: uint8(g.rng.Intn(256)), G: uint8(g.rng.Intn(256)), B: uint8(g.rng.Intn(256))}
+}
+
+// Options are command line settings that only apply to a single run.
+type Options struct {
+	RunFor   float64
+	NoAudio  bool
+	Software bool
+
+	RequireAccelerated bool
+	WindowFlags        string
+	WatchConfig        bool
+	FrameTimeLog       string
+	BenchFrames        int
+	Bundle             string
+
+	// Width and Height are the window size, 0 for the config's. VSync is
+	// nil unless -vsync was given.
+	Width, Height int
+	Fullscreen    bool
+	VSync         *bool
+	Mute          bool
+}
+
+// override puts the settings given on the command line over those of the
+// config file, which they take precedence over.
+func (o *Options) override(cfg *Config) {
+	if o.WindowFlags != "" {
+		cfg.WindowFlags = o.WindowFlags
+	}
+	if o.Fullscreen {
+		cfg.WindowFlags += ",fullscreen-desktop"
+	}
+	if o.Width > 0 {
+		cfg.WindowWidth = int32(o.Width)
+	}
+	if o.Height > 0 {
+		cfg.WindowHeight = int32(o.Height)
+	}
+	switch {
+	case o.VSync == nil:
+	case *o.VSync:
+		cfg.PacingMode = pacingVSync
+	case cfg.PacingMode == pacingVSync:
+		cfg.PacingMode = pacingDelay
+	}
+}
+
+func parseOptions() *Options {
+	opts := &Options{}
+	flag.Float64Var(&opts.RunFor, "run-for", 0, "quit after `seconds` (0 runs until closed)")
+	flag.BoolVar(&opts.NoAudio, "no-audio", false, "run without opening an audio device")
+	flag.BoolVar(&opts.Software, "software", false, "use the software renderer")
+	flag.BoolVar(&opts.RequireAccelerated, "require-accelerated", false, "exit with an error if only software rendering is available")
+	flag.StringVar(&opts.WindowFlags, "window-flags", "", "extra window `flags`, e.g. \"resizable,highdpi,borderless\" (overrides the config)")
+	flag.BoolVar(&opts.WatchConfig, "watch-config", false, "re-apply "+configPath+" when it changes on disk")
+	flag.IntVar(&opts.BenchFrames, "bench-frames", 0, "draw `N` frames as fast as possible, print their timings and quit")
+	flag.StringVar(&opts.FrameTimeLog, "frametime-log", "", "write every frame's duration to a CSV `file` on exit or F9")
+	flag.StringVar(&opts.Bundle, "bundle", "", "load assets from the zip archive at `path`, falling back to loose files")
+	flag.IntVar(&opts.Width, "width", 0, "open the window `pixels` wide (overrides the config)")
+	flag.IntVar(&opts.Height, "height", 0, "open the window `pixels` high (overrides the config)")
+	flag.BoolVar(&opts.Fullscreen, "fullscreen", false, "start in borderless fullscreen")
+	vsync := flag.Bool("vsync", false, "pace frames with vsync, or with -vsync=false by waiting instead (overrides the config)")
+	flag.BoolVar(&opts.Mute, "mute", false, "start muted")
+	flag.Parse()
+
+	given := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	if given["vsync"] {
+		opts.VSync = vsync
+	}
+
+	if opts.Software && opts.RequireAccelerated {
+		fmt.Fprintln(os.Stderr, "-software and -require-accelerated are mutually exclusive")
+		os.Exit(2)
+	}
+	if opts.BenchFrames < 0 {
+		fmt.Fprintln(os.Stderr, "-bench-frames must not be negative")
+		os.Exit(2)
+	}
+	if given["width"] && opts.Width < 1 || given["height"] && opts.Height < 1 {
+		fmt.Fprintln(os.Stderr, "-width and -height must be positive")
+		os.Exit(2)
+	}
+	return opts
 }
 
 func main() {
-	err := initSDL()
+	if err := run(); err != nil {
+		slog.Error("game stopped", "err", err)
+		os.Exit(1)
+	}
+}
+
+// run plays the game, returning an error if it had to stop. The cleanup is
+// deferred here rather than in main so that it runs before exiting with an
+// error.
+func run() error {
+	opts := parseOptions()
+
+	// Interrupting or terminating the game quits it like closing the
+	// window, so that the deferred cleanup runs and the audio device is
+	// closed properly.
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	err := initSDL(opts)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	defer closeSDL()
 
-	g := NewGame()
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if _, err := parseWindowFlags(opts.WindowFlags); err != nil {
+		return fmt.Errorf("Error in -window-flags: %v", err)
+	}
+	opts.override(cfg)
+
+	logs := NewLogBuffer(os.Stdout, logBufferLines)
+	logLevel.Set(cfg.logLevel())
+	slog.SetDefault(newLogger(logs))
+
+	g, err := NewGame(cfg, opts, logs)
+	if err != nil {
+		return err
+	}
 	defer g.Close()
 
-	g.Run()
+	reason, err := g.Run(stop)
+	// A second signal kills the game, should closing it get stuck.
+	signal.Stop(stop)
+	if reason == exitQuit {
+		g.fadeOutMusic()
+	}
+	return err
 }
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// unchanged marks the background color so that a change by randColor,
+// which always picks an alpha of 0, shows.
+var unchanged = sdl.Color{A: 255}
+
+func newColorCyclingGame(seed int64) *Game {
+	g := &Game{rng: rand.New(rand.NewSource(seed)), bgColor: unchanged}
+	g.setColorCycling(true)
+	return g
+}
+
+// cycleFrame runs updateColorCycling for a frame of dt seconds and reports
+// whether the color changed.
+func cycleFrame(g *Game, dt float64) bool {
+	g.bgColor = unchanged
+	g.updateColorCycling(dt)
+	return g.bgColor != unchanged
+}
+
+func TestUpdateColorCycling(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		frames []float64
+		want   []bool
+		timer  float64 // left towards the next change
+	}{
+		{"short frames", []float64{0.4, 0.4, 0.1, 0.1, 0.4}, []bool{false, false, false, true, false}, 0.4},
+		{"exactly a cycle", []float64{colorCycleSeconds}, []bool{true}, 0},
+		{"long frame changes once", []float64{3.5, 0.4}, []bool{true, false}, 0.9},
+		{"no time", []float64{0, 0}, []bool{false, false}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			g := newColorCyclingGame(1)
+			for i, dt := range tt.frames {
+				if got := cycleFrame(g, dt); got != tt.want[i] {
+					t.Errorf("frame %d of %vs: changed = %v, want %v", i, dt, got, tt.want[i])
+				}
+			}
+			if math.Abs(g.colorTimer-tt.timer) > 1e-9 {
+				t.Errorf("timer = %v, want %v", g.colorTimer, tt.timer)
+			}
+		})
+	}
+}
+
+func TestUpdateColorCyclingOff(t *testing.T) {
+	t.Parallel()
+	g := newColorCyclingGame(1)
+	g.setColorCycling(false)
+	if cycleFrame(g, 5) {
+		t.Error("the color changed with cycling off")
+	}
+	if g.colorTimer != 0 {
+		t.Errorf("timer = %v with cycling off, want 0", g.colorTimer)
+	}
+}
+
+func TestSetColorCyclingRestartsTimer(t *testing.T) {
+	t.Parallel()
+	g := newColorCyclingGame(1)
+	cycleFrame(g, 0.9)
+	g.setColorCycling(true)
+	if cycleFrame(g, 0.2) {
+		t.Error("the color changed 0.2s after cycling restarted")
+	}
+}
+
+func TestColorCyclingSeeded(t *testing.T) {
+	t.Parallel()
+	a, b := newColorCyclingGame(42), newColorCyclingGame(42)
+	for i := 0; i < 5; i++ {
+		a.updateColorCycling(colorCycleSeconds)
+		b.updateColorCycling(colorCycleSeconds)
+		if a.bgColor != b.bgColor {
+			t.Fatalf("change %d: colors %v and %v differ with the same seed", i, a.bgColor, b.bgColor)
+		}
+	}
+}
+
+func TestFixedSteps(t *testing.T) {
+	const step = 0.25 // exact in binary, so the sums are too
+	tests := []struct {
+		name          string
+		acc           float64
+		max           int
+		n             int
+		left, dropped float64
+	}{
+		{"less than a step", 0.125, 5, 0, 0.125, 0},
+		{"exactly a step", 0.25, 5, 1, 0, 0},
+		{"steps and a bit", 0.875, 5, 3, 0.125, 0},
+		{"exactly the cap", 1.25, 5, 5, 0, 0},
+		{"past the cap", 2.125, 5, 5, 0.125, 0.75},
+		{"cap of one", 1.0, 1, 1, 0, 0.75},
+		{"nothing", 0, 5, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		n, left, dropped := fixedSteps(tt.acc, step, tt.max)
+		if n != tt.n || left != tt.left || dropped != tt.dropped {
+			t.Errorf("%s: fixedSteps(%v, %v, %d) = %d, %v, %v, want %d, %v, %v", tt.name, tt.acc, step, tt.max, n, left, dropped, tt.n, tt.left, tt.dropped)
+		}
+	}
+}
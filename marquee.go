@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// How the title moves: bouncing off the window edges, scrolling right to
+// left as a marquee, or not at all.
+const (
+	textBounce  = "bounce"
+	textMarquee = "marquee"
+	textStatic  = "static"
+)
+
+// marqueeGap is the least space between the end of the title and the next
+// copy scrolling in behind it.
+const marqueeGap = 80
+
+// marqueeSpacing is the distance between copies of a scrolling title: the
+// window width, so a copy enters on the right as the title leaves on the
+// left, or more for a title too wide to leave room for the gap.
+func (g *Game) marqueeSpacing() float64 {
+	return math.Max(float64(g.width), float64(g.textRect.W+marqueeGap))
+}
+
+// scrollText moves the title left at the marquee speed, moving it on to the
+// next copy once it has scrolled off the left edge. The trail starts over
+// rather than follow it there.
+func (g *Game) scrollText(dt float64) {
+	g.textPos.X -= g.cfg.MarqueeSpeed * dt
+	if g.textPos.X+float64(g.textRect.W) <= 0 {
+		g.textPos.X += g.marqueeSpacing()
+		g.textGhosts.Reset()
+	}
+	g.textRect.X, g.textRect.Y = int32(math.Round(g.textPos.X)), int32(math.Round(g.textPos.Y))
+}
+
+// textRects is where the title is drawn: once, or in marquee mode also the
+// copies following it that are in view.
+func (g *Game) textRects() []sdl.Rect {
+	rects := []sdl.Rect{*g.textRect}
+	if g.cfg.TextMode != textMarquee {
+		return rects
+	}
+	spacing := g.marqueeSpacing()
+	for x := g.textPos.X + spacing; x < float64(g.width); x += spacing {
+		r := *g.textRect
+		r.X = int32(math.Round(x))
+		rects = append(rects, r)
+	}
+	return rects
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	menuWidth   = 360
+	menuPadding = 16
+)
+
+type menuItem struct {
+	label  string
+	value  func() string
+	change func(delta int)
+}
+
+// optionsMenu is the in-game settings overlay. While it is open it takes the
+// arrow keys: Up/Down select an item, Left/Right/Enter change it.
+type optionsMenu struct {
+	open     bool
+	selected int
+	items    []menuItem
+}
+
+func (g *Game) newOptionsMenu() *optionsMenu {
+	edgeItem := func(label string, edge *string) menuItem {
+		return menuItem{
+			label: label,
+			value: func() string { return *edge },
+			change: func(delta int) {
+				*edge = cycle([]string{edgeClamp, edgeBounce, edgeWrap}, *edge, delta)
+			},
+		}
+	}
+	return &optionsMenu{items: []menuItem{
+		{
+			label:  "Tint animation",
+			value:  func() string { return onOff(g.tintAnimation) },
+			change: func(int) { g.tintAnimation = !g.tintAnimation },
+		},
+		{
+			label:  "Sprite jitter",
+			value:  func() string { return onOff(g.jitter) },
+			change: func(int) { g.jitter = !g.jitter },
+		},
+		{
+			label:  "Flocking",
+			value:  func() string { return onOff(g.flocking) },
+			change: func(int) { g.flocking = !g.flocking },
+		},
+		{
+			label:  "Split screen",
+			value:  func() string { return onOff(g.splitScreen) },
+			change: func(int) { g.splitScreen = !g.splitScreen },
+		},
+		{
+			label:  "HUD blur",
+			value:  func() string { return onOff(g.hudBlur) },
+			change: func(int) { g.hudBlur = !g.hudBlur },
+		},
+		{
+			label:  "BPM pulse",
+			value:  func() string { return onOff(g.bpmPulse) },
+			change: func(int) { g.bpmPulse = !g.bpmPulse },
+		},
+		{
+			label:  "Afterimage",
+			value:  func() string { return onOff(g.afterimage) },
+			change: func(int) { g.afterimage = !g.afterimage },
+		},
+		{
+			label:  "Text trail",
+			value:  func() string { return onOff(g.textTrail) },
+			change: func(int) { g.setTextTrail(!g.textTrail) },
+		},
+		{
+			label:  "Reduced motion",
+			value:  func() string { return onOff(g.cfg.ReducedMotion) },
+			change: func(int) { g.cfg.ReducedMotion = !g.cfg.ReducedMotion },
+		},
+		edgeItem("Left edge", &g.cfg.LeftBehavior),
+		edgeItem("Right edge", &g.cfg.RightBehavior),
+		edgeItem("Top edge", &g.cfg.TopBehavior),
+		edgeItem("Bottom edge", &g.cfg.BottomBehavior),
+		{
+			label: "Pause on menu",
+			value: func() string { return onOff(g.cfg.PauseOnMenu) },
+			change: func(int) {
+				g.cfg.PauseOnMenu = !g.cfg.PauseOnMenu
+				g.syncMenuPause()
+			},
+		},
+		{
+			label: "Preset",
+			value: func() string { return orNone(g.preset) },
+			change: func(delta int) {
+				names := ListPresets()
+				if len(names) == 0 {
+					slog.Info("no presets saved", "dir", presetDir)
+					return
+				}
+				if err := g.LoadPreset(cycle(names, g.preset, delta)); err != nil {
+					slog.Error("could not load preset", "err", err)
+				}
+			},
+		},
+		{
+			label: "Save preset",
+			value: func() string { return g.presetSaveName() },
+			change: func(int) {
+				if err := g.SavePreset(g.presetSaveName()); err != nil {
+					slog.Error("could not save preset", "err", err)
+				}
+			},
+		},
+	}}
+}
+
+func (g *Game) toggleMenu() {
+	g.menu.open = !g.menu.open
+	g.syncMenuPause()
+}
+
+// handleMenuKey processes a key press while the menu is open and reports
+// whether the menu consumed it.
+func (g *Game) handleMenuKey(key sdl.Keycode) bool {
+	m := g.menu
+	switch key {
+	case sdl.K_UP:
+		m.selected = (m.selected + len(m.items) - 1) % len(m.items)
+	case sdl.K_DOWN:
+		m.selected = (m.selected + 1) % len(m.items)
+	case sdl.K_LEFT:
+		m.items[m.selected].change(-1)
+	case sdl.K_RIGHT, sdl.K_RETURN:
+		m.items[m.selected].change(1)
+	default:
+		return false
+	}
+	return true
+}
+
+// frozen reports whether gameplay updates are suspended.
+func (g *Game) frozen() bool {
+	return g.menu.open && g.cfg.PauseOnMenu
+}
+
+// syncMenuPause pauses the music while the game is paused or frozen by the
+// menu and resumes it afterwards.
+func (g *Game) syncMenuPause() {
+	if g.music == nil {
+		return
+	}
+	hold := g.paused || g.frozen()
+	if hold && !g.musicHeld && g.musicPlaying() && !g.musicPaused() {
+		g.pauseMusic(true)
+		g.musicHeld = true
+	}
+	if !hold && g.musicHeld {
+		g.pauseMusic(false)
+		g.musicHeld = false
+	}
+}
+
+func (g *Game) renderMenu() {
+	m := g.menu
+	lineHeight := g.lineHeight(fontUI) + 10
+	h := int32(len(m.items)+1)*lineHeight + 2*menuPadding
+	panel := sdl.Rect{X: (g.width - menuWidth) / 2, Y: (g.height - h) / 2, W: menuWidth, H: h}
+
+	g.drawPanel(panel, 200)
+
+	white := sdl.Color{R: 255, G: 255, B: 255, A: 255}
+	highlight := sdl.Color{R: 255, G: 220, B: 80, A: 255}
+	x, y := panel.X+menuPadding, panel.Y+menuPadding
+	g.drawText(fontUI, "Options", white, x, y)
+	y += lineHeight
+	for i, item := range m.items {
+		c := white
+		prefix := "  "
+		if i == m.selected {
+			c = highlight
+			prefix = "> "
+		}
+		g.drawText(fontUI, fmt.Sprintf("%s%s: %s", prefix, item.label, item.value()), c, x, y)
+		y += lineHeight
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// cycle returns the value delta steps away from current in values, wrapping
+// around at either end.
+func cycle(values []string, current string, delta int) string {
+	for i, v := range values {
+		if v == current {
+			return values[((i+delta)%len(values)+len(values))%len(values)]
+		}
+	}
+	return values[0]
+}
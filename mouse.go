@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// handleMouseButton moves the player to a left click and plays the bounce
+// sound on a right click. SDL already reports the position in the
+// renderer's logical coordinates; it is then mapped through the viewport
+// clicked in. Clicks do nothing while the game is paused or waiting on a
+// menu or question, and the sprite stays put while the free camera is on.
+func (g *Game) handleMouseButton(e *sdl.MouseButtonEvent) {
+	if e.Type != sdl.MOUSEBUTTONDOWN || g.paused || g.intro || g.menu.open || g.confirmingQuit {
+		return
+	}
+	switch e.Button {
+	case sdl.BUTTON_LEFT:
+		p, ok := g.screenToWorld(e.X, e.Y)
+		if !ok || g.freeCamera {
+			return
+		}
+		g.teleportPlayer(p)
+	case sdl.BUTTON_RIGHT:
+		g.playSound(soundBounce)
+	}
+}
+
+// teleportPlayer centers the player on p, kept fully inside the window.
+func (g *Game) teleportPlayer(p Vec2) {
+	b := &g.player.box
+	b.X = clampf(p.X-b.W/2, 0, float64(g.width)-b.W)
+	b.Y = clampf(p.Y-b.H/2, 0, float64(g.height)-b.H)
+	slog.Debug("sprite teleported", "box", *b)
+}
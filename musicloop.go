@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"unsafe"
+
+	"github.com/veandco/go-sdl2/mix"
+)
+
+// musicChannel is the mixer channel reserved for tracks with a loop region,
+// which play as decoded chunks rather than as mixer music. Sounds never play
+// on it.
+const musicChannel = 0
+
+// loopedTrack is a track with a loop region, split into the part before it,
+// played once, and the region itself, repeated until the track is stopped.
+// intro is nil when the region starts at the beginning. Both are views of
+// the decoded track and belong to the asset manager.
+type loopedTrack struct {
+	intro, loop *mix.Chunk
+}
+
+func validateLoopRegion(i int, t MusicTrack) error {
+	if t.LoopStart < 0 {
+		return fmt.Errorf("music: loopStart of track %d must be 0 or more, got %v", i+1, t.LoopStart)
+	}
+	if t.LoopEnd != 0 && t.LoopEnd <= t.LoopStart {
+		return fmt.Errorf("music: loopEnd of track %d must be 0 (the end) or after loopStart, got %v", i+1, t.LoopEnd)
+	}
+	return nil
+}
+
+// hasLoopRegion reports whether the track loops part of itself.
+func (t MusicTrack) hasLoopRegion() bool {
+	return t.LoopStart > 0 || t.LoopEnd > 0
+}
+
+// loadLoopedTrack decodes the whole of a track into memory, as SDL_mixer
+// can't loop music between two points, and splits it at the loop region.
+func (g *Game) loadLoopedTrack(t MusicTrack) (*loopedTrack, error) {
+	full, err := g.assets.LoadSound("music "+t.Path, t.Path)
+	if err != nil {
+		return nil, err
+	}
+	freq, format, channels, _, err := mix.QuerySpec()
+	if err != nil {
+		return nil, fmt.Errorf("Error querying audio spec: %v", err)
+	}
+	frame := int(format&0xFF) / 8 * channels
+	samples := chunkSamples(full)
+	offset := func(seconds float64) int {
+		return min(int(seconds*float64(freq))*frame, len(samples))
+	}
+	start, end := offset(t.LoopStart), len(samples)
+	if t.LoopEnd > 0 {
+		end = offset(t.LoopEnd)
+	}
+	if start >= end {
+		return nil, fmt.Errorf("loop region from %vs is past the end of the track", t.LoopStart)
+	}
+
+	var l loopedTrack
+	if start > 0 {
+		if l.intro, err = g.assets.SoundSlice(t.Path+" intro", t.Path, samples[:start]); err != nil {
+			return nil, err
+		}
+	}
+	if l.loop, err = g.assets.SoundSlice(t.Path+" loop", t.Path, samples[start:end]); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// playLooped starts a looped track on musicChannel, fading it in over
+// fadeMs milliseconds if that isn't 0.
+func (g *Game) playLooped(l *loopedTrack, fadeMs int) error {
+	g.looping = l
+	g.introDone.Store(false)
+	chunk, loops := l.intro, 0
+	if chunk == nil {
+		chunk, loops = l.loop, -1
+	}
+	var err error
+	if fadeMs > 0 {
+		_, err = chunk.FadeIn(musicChannel, loops, fadeMs)
+	} else {
+		_, err = chunk.Play(musicChannel, loops)
+	}
+	if err != nil {
+		g.looping = nil
+		return err
+	}
+	mix.Volume(musicChannel, g.volume)
+	return nil
+}
+
+// channelFinished is called by SDL_mixer, on the audio thread, whenever a
+// channel stops. Mixer functions can't be called from there, so the intro
+// finishing is only noted for updateLoopedMusic to go on from.
+func (g *Game) channelFinished(channel int) {
+	if channel == musicChannel {
+		g.introDone.Store(true)
+	}
+}
+
+// updateLoopedMusic starts repeating the loop region once the intro is
+// done. Between the intro ending and the next frame there is a gap of up
+// to a frame.
+func (g *Game) updateLoopedMusic() {
+	if g.looping == nil || !g.introDone.Swap(false) || g.looping.intro == nil {
+		return
+	}
+	if _, err := g.looping.loop.Play(musicChannel, -1); err != nil {
+		slog.Error("could not play music loop", "err", err)
+		g.looping = nil
+		return
+	}
+	mix.Volume(musicChannel, g.volume)
+}
+
+// musicPlaying reports whether the current track is still playing, looped
+// or not. A finished intro counts as playing, as its loop follows.
+func (g *Game) musicPlaying() bool {
+	if g.looping != nil {
+		return mix.Playing(musicChannel) > 0 || g.introDone.Load()
+	}
+	return mix.PlayingMusic()
+}
+
+func (g *Game) musicPaused() bool {
+	if g.looping != nil {
+		return mix.Paused(musicChannel) > 0
+	}
+	return mix.PausedMusic()
+}
+
+func (g *Game) musicFading() bool {
+	if g.looping != nil {
+		return mix.FadingChannel(musicChannel) != mix.NO_FADING
+	}
+	return mix.FadingMusic() != mix.NO_FADING
+}
+
+// pauseMusic pauses or resumes the current track, looped or not.
+func (g *Game) pauseMusic(on bool) {
+	switch {
+	case g.looping != nil && on:
+		mix.Pause(musicChannel)
+	case g.looping != nil:
+		mix.Resume(musicChannel)
+	case on:
+		mix.PauseMusic()
+	default:
+		mix.ResumeMusic()
+	}
+}
+
+// stopLooped stops a looped track from repeating any further.
+func (g *Game) stopLooped() {
+	g.looping = nil
+	mix.HaltChannel(musicChannel)
+}
+
+// sliceData is the address of the first byte of b, for handing memory that
+// stays alive elsewhere to the mixer.
+func sliceData(b []byte) *uint8 {
+	return (*uint8)(unsafe.Pointer(unsafe.SliceData(b)))
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// Names of the overlays drawn over the scene, used as keys of the
+// overlayOrder setting.
+const (
+	overlayEffect      = "effect"
+	overlayFPS         = "fps"
+	overlayDebug       = "debug"
+	overlayPaused      = "paused"
+	overlayInputs      = "inputs"
+	overlayMenu        = "menu"
+	overlayConsole     = "console"
+	overlayCrosshair   = "crosshair"
+	overlayQuitConfirm = "quitConfirm"
+	overlayIdleDim     = "idleDim"
+	overlayVolume      = "volume"
+	overlaySpriteCount = "spriteCount"
+)
+
+// overlayPriorities is the default stacking of the overlays, lowest drawn
+// first. The overlay effect goes right over the scene, under the HUD, and the
+// crosshair over the HUD so it can point at the menu. The quit question goes over everything but the idle dimming, which has to
+// cover it too.
+var overlayPriorities = map[string]int{
+	overlayEffect:      5,
+	overlayFPS:         10,
+	overlaySpriteCount: 15,
+	overlayDebug:       20,
+	overlayPaused:      30,
+	overlayInputs:      40,
+	overlayVolume:      45,
+	overlayMenu:        50,
+	overlayConsole:     60,
+	overlayCrosshair:   65,
+	overlayQuitConfirm: 70,
+	overlayIdleDim:     100,
+}
+
+// overlay is something drawn over the scene, such as a HUD panel, whenever
+// visible says so.
+type overlay struct {
+	name    string
+	visible func() bool
+	draw    func()
+}
+
+func validateFocusModeKeeps(names []string) error {
+	for _, name := range names {
+		if _, ok := overlayPriorities[name]; !ok {
+			return fmt.Errorf("focusModeKeeps: unknown overlay %q", name)
+		}
+	}
+	return nil
+}
+
+func validateOverlayOrder(order map[string]int) error {
+	for name := range order {
+		if _, ok := overlayPriorities[name]; !ok {
+			return fmt.Errorf("overlayOrder: unknown overlay %q", name)
+		}
+	}
+	return nil
+}
+
+// registerOverlays adds the built-in overlays.
+func (g *Game) registerOverlays() {
+	g.addOverlay(overlayEffect, func() bool { return g.cfg.OverlayEffect != effectNone }, g.renderEffect)
+	g.addOverlay(overlayFPS, func() bool { return g.showFPS }, g.renderFPS)
+	g.addOverlay(overlaySpriteCount, g.spriteCountVisible, g.renderSpriteCount)
+	g.addOverlay(overlayDebug, func() bool { return g.showDebug }, g.renderDebugOverlay)
+	g.addOverlay(overlayPaused, func() bool { return g.paused && g.state == g.playing }, g.renderPaused)
+	g.addOverlay(overlayInputs, func() bool { return g.showInputs }, g.renderInputs)
+	g.addOverlay(overlayVolume, g.volumeVisible, g.renderVolume)
+	g.addOverlay(overlayMenu, func() bool { return g.menu.open }, g.renderMenu)
+	g.addOverlay(overlayConsole, func() bool { return g.showConsole }, g.renderConsole)
+	g.addOverlay(overlayCrosshair, func() bool { return g.cfg.ShowCrosshair }, g.renderCrosshair)
+	g.addOverlay(overlayQuitConfirm, func() bool { return g.confirmingQuit }, g.renderQuitConfirm)
+	g.addOverlay(overlayIdleDim, func() bool { return g.cfg.IdleDimSeconds > 0 }, g.renderIdleDim)
+}
+
+// overlayPriority is where the named overlay is stacked: as set by
+// overlayOrder, or else its default.
+func (g *Game) overlayPriority(name string) int {
+	if p, ok := g.cfg.OverlayOrder[name]; ok {
+		return p
+	}
+	return overlayPriorities[name]
+}
+
+// addOverlay adds an overlay, replacing any other of the same name.
+func (g *Game) addOverlay(name string, visible func() bool, draw func()) {
+	g.removeOverlay(name)
+	g.overlays = append(g.overlays, overlay{name: name, visible: visible, draw: draw})
+	g.sortOverlays()
+}
+
+func (g *Game) removeOverlay(name string) {
+	g.overlays = slices.DeleteFunc(g.overlays, func(o overlay) bool { return o.name == name })
+}
+
+// sortOverlays puts the overlays in drawing order. Overlays of the same
+// priority keep the order they were added in.
+func (g *Game) sortOverlays() {
+	slices.SortStableFunc(g.overlays, func(a, b overlay) int {
+		return g.overlayPriority(a.name) - g.overlayPriority(b.name)
+	})
+}
+
+// renderOverlays draws the visible overlays in priority order. In focus
+// mode only those in focusModeKeeps are drawn.
+func (g *Game) renderOverlays() {
+	for _, o := range g.overlays {
+		if g.focusMode && !slices.Contains(g.cfg.FocusModeKeeps, o.name) {
+			continue
+		}
+		if o.visible() {
+			o.draw()
+		}
+	}
+}
+
+// toggleFocusMode hides all overlays at once, for clean recordings and
+// screenshots, or brings them back as they were.
+func (g *Game) toggleFocusMode() {
+	g.focusMode = !g.focusMode
+	slog.Debug("focus mode", "on", g.focusMode)
+}
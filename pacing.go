@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Frame pacing modes, picked with the pacingMode setting. Each trades CPU
+// for how closely frames keep to targetFPS:
+//
+//   - vsync lets Present wait for the display's refresh, which costs no CPU
+//     and never tears, but adds up to a refresh of latency and ignores
+//     targetFPS. Without working vsync it falls back to delay.
+//   - delay sleeps off what is left of the frame with SDL_Delay. It is cheap
+//     too, but the OS scheduler can oversleep by a millisecond or more.
+//   - spin sleeps until spinMarginMillis before the frame is due and busy
+//     waits the rest, for frames within microseconds of the target at the
+//     cost of some CPU.
+//   - uncapped doesn't wait at all, for the lowest latency and highest frame
+//     rate at the cost of a whole core. -bench-frames always runs like this.
+const (
+	pacingVSync    = "vsync"
+	pacingDelay    = "delay"
+	pacingSpin     = "spin"
+	pacingUncapped = "uncapped"
+)
+
+// spinMarginMillis is how long before a frame is due spin pacing stops
+// sleeping, to allow for the OS oversleeping.
+const spinMarginMillis = 2
+
+func validatePacingMode(mode string) error {
+	switch mode {
+	case pacingVSync, pacingDelay, pacingSpin, pacingUncapped:
+		return nil
+	}
+	return fmt.Errorf("pacingMode must be %q, %q, %q or %q, got %q", pacingVSync, pacingDelay, pacingSpin, pacingUncapped, mode)
+}
+
+// pacer returns how to wait out a frame begun at the given performance
+// counter, for the configured pacing mode.
+func (g *Game) pacer() func(frameStart uint64) {
+	switch g.cfg.PacingMode {
+	case pacingVSync:
+		if g.vsyncWorks() {
+			return func(uint64) {}
+		}
+		slog.Info("vsync isn't in effect, pacing frames with delays instead")
+		return g.delayFrame
+	case pacingSpin:
+		return g.spinFrame
+	case pacingUncapped:
+		return func(uint64) {}
+	}
+	return g.delayFrame
+}
+
+// vsyncWorks reports whether presenting waits for vsync: the renderer was
+// created with it and, if it was probed, presents really do wait.
+func (g *Game) vsyncWorks() bool {
+	return g.rendererVSync() && (!g.vsync.probed || g.vsync.active)
+}
+
+// rendererVSync reports whether the renderer was created to present with
+// vsync.
+func (g *Game) rendererVSync() bool {
+	info, err := g.renderer.GetInfo()
+	return err == nil && info.Flags&sdl.RENDERER_PRESENTVSYNC != 0
+}
+
+// frameRemaining is how many milliseconds are left of the frame begun at
+// frameStart to keep to targetFPS, negative if it ran over.
+func (g *Game) frameRemaining(frameStart uint64) float64 {
+	return 1000/float64(g.targetFPS) - perfMillis(frameStart, sdl.GetPerformanceCounter())
+}
+
+// delayFrame sleeps off what is left of the frame, not at all if the frame
+// took longer.
+func (g *Game) delayFrame(frameStart uint64) {
+	if remaining := g.frameRemaining(frameStart); remaining >= 1 {
+		sdl.Delay(uint32(remaining))
+	}
+}
+
+// spinFrame sleeps through most of what is left of the frame and busy waits
+// the last spinMarginMillis.
+func (g *Game) spinFrame(frameStart uint64) {
+	if remaining := g.frameRemaining(frameStart) - spinMarginMillis; remaining >= 1 {
+		sdl.Delay(uint32(remaining))
+	}
+	for g.frameRemaining(frameStart) > 0 {
+	}
+}
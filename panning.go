@@ -0,0 +1,43 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/veandco/go-sdl2/mix"
+)
+
+// bounceChannel is the mixer channel reserved for the bounce sound, so its
+// panning can be set before each bounce starts playing. A bounce cuts off
+// the one before it.
+const bounceChannel = musicChannel + 1
+
+// panForX maps x across a window width wide to the left and right volumes
+// of a sound coming from there: all left at the left edge, all right at the
+// right edge and evenly split in the middle.
+func panForX(x, width int32) (left, right uint8) {
+	if width <= 0 {
+		return 255, 255
+	}
+	right = uint8(255 * clampf(float64(x)/float64(width), 0, 1))
+	return 255 - right, right
+}
+
+// playPannedBounce plays chunk on bounceChannel at volume, panned to x when
+// stereoPanning is on.
+func (g *Game) playPannedBounce(chunk *mix.Chunk, volume int, x float64) {
+	if chunk == nil {
+		return
+	}
+	left, right := uint8(255), uint8(255)
+	if g.cfg.StereoPanning {
+		left, right = panForX(int32(x), g.width)
+	}
+	// Channels keep their panning and volume, so set them for every bounce.
+	if err := mix.SetPanning(bounceChannel, left, right); err != nil {
+		slog.Debug("could not pan the bounce sound", "err", err)
+	}
+	mix.Volume(bounceChannel, volume)
+	if _, err := chunk.Play(bounceChannel, g.cfg.Sounds[soundBounce].Loops); err != nil {
+		slog.Debug("sound dropped", "event", soundBounce, "err", err)
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestPanForX(t *testing.T) {
+	tests := []struct {
+		name        string
+		x, width    int32
+		left, right uint8
+	}{
+		{"left edge", 0, 800, 255, 0},
+		{"right edge", 800, 800, 0, 255},
+		{"middle", 400, 800, 128, 127},
+		{"quarter", 200, 800, 192, 63},
+		{"past the left edge", -50, 800, 255, 0},
+		{"past the right edge", 900, 800, 0, 255},
+		{"no width", 100, 0, 255, 255},
+		{"negative width", 100, -800, 255, 255},
+	}
+	for _, tt := range tests {
+		left, right := panForX(tt.x, tt.width)
+		if left != tt.left || right != tt.right {
+			t.Errorf("%s: panForX(%d, %d) = %d, %d, want %d, %d", tt.name, tt.x, tt.width, left, right, tt.left, tt.right)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Bursts of particles, e.g. where the title bounces off a wall. Speeds are
+// in pixels per second and lifetimes in seconds.
+const (
+	particleSize     = 3
+	particleMinSpeed = 60.0
+	particleMaxSpeed = 180.0
+	particleMinLife  = 0.4
+	particleMaxLife  = 0.8
+)
+
+// Particle is a dot flying off in a straight line and fading out until its
+// lifetime is up.
+type Particle struct {
+	pos, vel  Vec2
+	age, life float64
+	color     sdl.Color
+}
+
+func (p *Particle) alive() bool {
+	return p.age < p.life
+}
+
+// ParticleSystem keeps a fixed pool of particles, so spawning and updating
+// them never allocates. Spawning takes the slots in turn, which reuses dead
+// particles and, when all are alive, recycles the oldest first. Particles
+// are random only through rng, so a seeded game spawns the same ones.
+type ParticleSystem struct {
+	pool []Particle
+	next int // the slot spawned into next, holding the oldest particle
+	rng  *rand.Rand
+}
+
+// NewParticleSystem makes a system of at most max particles at once.
+func NewParticleSystem(max int, rng *rand.Rand) *ParticleSystem {
+	return &ParticleSystem{pool: make([]Particle, max), rng: rng}
+}
+
+// Spawn bursts count particles of color out from x, y in all directions.
+func (s *ParticleSystem) Spawn(x, y int32, count int, color sdl.Color) {
+	for i := 0; i < count; i++ {
+		angle := s.rng.Float64() * 2 * math.Pi
+		speed := particleMinSpeed + s.rng.Float64()*(particleMaxSpeed-particleMinSpeed)
+		s.pool[s.next] = Particle{
+			pos:   Vec2{X: float64(x), Y: float64(y)},
+			vel:   Vec2{X: math.Cos(angle) * speed, Y: math.Sin(angle) * speed},
+			life:  particleMinLife + s.rng.Float64()*(particleMaxLife-particleMinLife),
+			color: color,
+		}
+		s.next = (s.next + 1) % len(s.pool)
+	}
+}
+
+// Update moves the living particles on by dt seconds.
+func (s *ParticleSystem) Update(dt float64) {
+	for i := range s.pool {
+		p := &s.pool[i]
+		if !p.alive() {
+			continue
+		}
+		p.age += dt
+		p.pos = p.pos.Add(p.vel.Scale(dt))
+	}
+}
+
+// Render draws the living particles as seen through cam, each fading out
+// over its lifetime. The draw color and blend mode are kept.
+func (s *ParticleSystem) Render(r *sdl.Renderer, cam Camera) {
+	pr, pg, pb, pa, _ := r.GetDrawColor()
+	var mode sdl.BlendMode
+	r.GetDrawBlendMode(&mode)
+	defer r.SetDrawColor(pr, pg, pb, pa)
+	defer r.SetDrawBlendMode(mode)
+
+	r.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+	for i := range s.pool {
+		p := &s.pool[i]
+		if !p.alive() {
+			continue
+		}
+		fade := 1 - p.age/p.life
+		r.SetDrawColor(p.color.R, p.color.G, p.color.B, uint8(float64(p.color.A)*fade))
+		dst := cam.Apply(sdl.FRect{X: float32(p.pos.X) - particleSize/2, Y: float32(p.pos.Y) - particleSize/2, W: particleSize, H: particleSize})
+		r.FillRectF(&dst)
+	}
+}
+
+// Alive counts the living particles.
+func (s *ParticleSystem) Alive() int {
+	n := 0
+	for i := range s.pool {
+		if s.pool[i].alive() {
+			n++
+		}
+	}
+	return n
+}
+
+// bounceParticles bursts particles from where the title touches a wall:
+// the middle of its left or right edge for a bounce off the side walls,
+// horizontal, or of its top or bottom edge otherwise.
+func (g *Game) bounceParticles(horizontal bool) {
+	if g.cfg.BounceParticles == 0 {
+		return
+	}
+	p, w, h := g.textPos, g.textRect.W, g.textRect.H
+	x, y := int32(p.X)+w/2, int32(p.Y)+h/2
+	switch {
+	case horizontal && p.X <= 0:
+		x = int32(p.X)
+	case horizontal:
+		x = int32(p.X) + w
+	case p.Y <= 0:
+		y = int32(p.Y)
+	default:
+		y = int32(p.Y) + h
+	}
+	g.particles.Spawn(x, y, g.cfg.BounceParticles, g.cfg.TextColor)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var particleColor = sdl.Color{R: 255, G: 200, B: 80, A: 255}
+
+// particleFrame spawns a burst into s and moves everything on by one frame,
+// as a bouncing title does.
+func particleFrame(s *ParticleSystem, burst int) {
+	s.Spawn(400, 300, burst, particleColor)
+	s.Update(1.0 / 60)
+}
+
+func TestParticleSystemRecyclesOldest(t *testing.T) {
+	s := NewParticleSystem(4, rand.New(rand.NewSource(1)))
+	s.Spawn(0, 0, 3, particleColor)
+	s.Update(0.1)
+	s.Spawn(100, 100, 3, particleColor)
+	if got := s.Alive(); got != 4 {
+		t.Fatalf("Alive() = %d, want the cap of 4", got)
+	}
+	// The two oldest were recycled, leaving one of the first burst, which
+	// has aged, and the three new ones, which haven't.
+	aged := 0
+	for _, p := range s.pool {
+		if p.age > 0 {
+			aged++
+		}
+	}
+	if aged != 1 {
+		t.Errorf("%d of the first burst are left, want 1", aged)
+	}
+}
+
+func TestParticleSystemDeterministic(t *testing.T) {
+	a := NewParticleSystem(64, rand.New(rand.NewSource(7)))
+	b := NewParticleSystem(64, rand.New(rand.NewSource(7)))
+	for i := 0; i < 10; i++ {
+		particleFrame(a, 12)
+		particleFrame(b, 12)
+	}
+	if !reflect.DeepEqual(a.pool, b.pool) {
+		t.Error("particles differ with the same seed")
+	}
+}
+
+func TestParticleSystemDoesNotAllocate(t *testing.T) {
+	max := DefaultConfig().MaxParticles
+	s := NewParticleSystem(max, rand.New(rand.NewSource(1)))
+	if allocs := testing.AllocsPerRun(100, func() { particleFrame(s, max) }); allocs != 0 {
+		t.Errorf("a frame of spawning and updating %d particles makes %v allocations, want 0", max, allocs)
+	}
+}
+
+// BenchmarkParticleSystem spawns the full pool every frame, recycling every
+// particle, and updates it.
+func BenchmarkParticleSystem(b *testing.B) {
+	max := DefaultConfig().MaxParticles
+	s := NewParticleSystem(max, rand.New(rand.NewSource(1)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		particleFrame(s, max)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import "github.com/veandco/go-sdl2/sdl"
+
+const (
+	pauseTitle   = "PAUSED"
+	pauseLabel   = "P to resume, . to step a frame"
+	pausePadding = 8
+	pauseDim     = 128
+)
+
+// togglePause pauses or resumes the game. The music pauses with it.
+func (g *Game) togglePause() {
+	g.setPaused(!g.paused)
+}
+
+func (g *Game) setPaused(on bool) {
+	g.paused = on
+	g.pausedByFocus = false
+	g.stepping = false
+	g.syncMenuPause()
+}
+
+// focusLost pauses the game when the window loses focus, and focusGained
+// resumes it, unless the player paused or resumed it themselves meanwhile.
+func (g *Game) focusLost() {
+	if !g.paused {
+		g.setPaused(true)
+		g.pausedByFocus = true
+	}
+}
+
+func (g *Game) focusGained() {
+	if g.pausedByFocus {
+		g.setPaused(false)
+	}
+}
+
+// stepFrame advances a paused game by one update on the next tick.
+func (g *Game) stepFrame() {
+	if g.paused {
+		g.stepping = true
+	}
+}
+
+// renderPaused dims the frozen scene and shows PAUSED in the middle of the
+// window, with the keys to resume or step underneath.
+func (g *Game) renderPaused() {
+	g.dimScreen(pauseDim)
+	title, ui := g.font(fontTitle), g.font(fontUI)
+	if title == nil || ui == nil {
+		return
+	}
+	tw, th, err := title.SizeUTF8(pauseTitle)
+	if err != nil {
+		return
+	}
+	lw, lh, err := ui.SizeUTF8(pauseLabel)
+	if err != nil {
+		return
+	}
+	white := sdl.Color{R: 255, G: 255, B: 255, A: 255}
+	y := (g.height - int32(th+pausePadding+lh)) / 2
+	g.drawText(fontTitle, pauseTitle, white, (g.width-int32(tw))/2, y)
+	g.drawText(fontUI, pauseLabel, white, (g.width-int32(lw))/2, y+int32(th)+pausePadding)
+}
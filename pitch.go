@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"math"
+	"unsafe"
+
+	"github.com/veandco/go-sdl2/mix"
+)
+
+// pitchSpread is how far the bounce variants are pitched up and down, as a
+// fraction of the original pitch.
+const pitchSpread = 0.08
+
+// mixChunk mirrors Mix_Chunk, whose fields mix.Chunk doesn't export, to
+// reach the decoded samples.
+type mixChunk struct {
+	allocated int32
+	buf       *uint8
+	len       uint32
+	volume    uint8
+}
+
+// chunkSamples returns a chunk's sample data, which is in the format the
+// audio device was opened with. It belongs to the chunk.
+func chunkSamples(chunk *mix.Chunk) []byte {
+	c := (*mixChunk)(unsafe.Pointer(chunk))
+	if c.buf == nil || c.len == 0 {
+		return nil
+	}
+	return unsafe.Slice(c.buf, c.len)
+}
+
+// sampleCodec reads single samples of an SDL audio format as floats from
+// -1 to 1.
+type sampleCodec struct {
+	size   int
+	float  bool
+	signed bool
+	order  binary.ByteOrder
+}
+
+func newSampleCodec(format uint16) (sampleCodec, error) {
+	c := sampleCodec{
+		size:   int(format&0xFF) / 8,
+		float:  format&0x100 != 0,
+		signed: format&0x8000 != 0,
+		order:  binary.ByteOrder(binary.LittleEndian),
+	}
+	if format&0x1000 != 0 {
+		c.order = binary.BigEndian
+	}
+	switch {
+	case c.float && c.size == 4, !c.float && (c.size == 1 || c.size == 2 || c.size == 4):
+		return c, nil
+	}
+	return c, fmt.Errorf("unsupported audio format %#x", format)
+}
+
+func (c sampleCodec) read(b []byte) float64 {
+	if c.float {
+		return float64(math.Float32frombits(c.order.Uint32(b)))
+	}
+	var v uint64
+	switch c.size {
+	case 1:
+		v = uint64(b[0])
+	case 2:
+		v = uint64(c.order.Uint16(b))
+	case 4:
+		v = uint64(c.order.Uint32(b))
+	}
+	bits := uint(c.size * 8)
+	full := float64(uint64(1) << (bits - 1))
+	if !c.signed {
+		return float64(v)/full - 1
+	}
+	// Sign extend before scaling.
+	return float64(int64(v<<(64-bits))>>(64-bits)) / full
+}
+
+// resample stretches interleaved frames of the given number of channels by
+// 1/ratio with linear interpolation, so that played at the same rate they
+// sound ratio times higher.
+func resample(samples []float64, channels int, ratio float64) []float64 {
+	frames := len(samples) / channels
+	n := int(float64(frames) / ratio)
+	out := make([]float64, n*channels)
+	for i := 0; i < n; i++ {
+		pos := float64(i) * ratio
+		j := int(pos)
+		frac := pos - float64(j)
+		k := min(j+1, frames-1)
+		for ch := 0; ch < channels; ch++ {
+			out[i*channels+ch] = lerp(samples[j*channels+ch], samples[k*channels+ch], frac)
+		}
+	}
+	return out
+}
+
+// encodeWAV makes a 16-bit PCM WAV file of interleaved samples from -1 to
+// 1, for the mixer to load and convert like a file from disk.
+func encodeWAV(samples []float64, freq, channels int) []byte {
+	var buf bytes.Buffer
+	data := len(samples) * 2
+	w := func(v any) { binary.Write(&buf, binary.LittleEndian, v) }
+	buf.WriteString("RIFF")
+	w(uint32(36 + data))
+	buf.WriteString("WAVEfmt ")
+	w(uint32(16))
+	w(uint16(1)) // PCM
+	w(uint16(channels))
+	w(uint32(freq))
+	w(uint32(freq * channels * 2))
+	w(uint16(channels * 2))
+	w(uint16(16))
+	buf.WriteString("data")
+	w(uint32(data))
+	for _, s := range samples {
+		w(int16(math.Round(clampf(s, -1, 1) * math.MaxInt16)))
+	}
+	return buf.Bytes()
+}
+
+// pitchVariants makes n copies of chunk pitched evenly from pitchSpread
+// below to pitchSpread above the original, by resampling its samples.
+func pitchVariants(chunk *mix.Chunk, n int) ([][]byte, []float64, error) {
+	freq, format, channels, _, err := mix.QuerySpec()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error querying audio spec: %v", err)
+	}
+	codec, err := newSampleCodec(format)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw := chunkSamples(chunk)
+	samples := make([]float64, len(raw)/codec.size)
+	for i := range samples {
+		samples[i] = codec.read(raw[i*codec.size:])
+	}
+	if len(samples) < channels {
+		return nil, nil, fmt.Errorf("sound is empty")
+	}
+
+	wavs := make([][]byte, n)
+	ratios := make([]float64, n)
+	for i := range wavs {
+		ratios[i] = 1 - pitchSpread + 2*pitchSpread*float64(i)/float64(n-1)
+		wavs[i] = encodeWAV(resample(samples, channels, ratios[i]), freq, channels)
+	}
+	return wavs, ratios, nil
+}
+
+// makeBounceVariants loads pitch shifted copies of the bounce sound for
+// playSound to pick from. Without them the bounce sound plays as it is.
+func (g *Game) makeBounceVariants() {
+	chunk := g.assets.Sound(soundBounce)
+	if chunk == nil || len(g.bounceVariants) > 0 {
+		return
+	}
+	wavs, ratios, err := pitchVariants(chunk, g.cfg.PitchVariants)
+	if err != nil {
+		slog.Warn("could not make bounce pitch variants", "err", err)
+		return
+	}
+	path := g.cfg.Sounds[soundBounce].Path
+	for i, wav := range wavs {
+		v, err := g.assets.LoadSoundData(fmt.Sprintf("%s at %.2fx pitch", soundBounce, ratios[i]), path, wav)
+		if err != nil {
+			slog.Warn("could not load bounce pitch variant", "err", err)
+			continue
+		}
+		g.bounceVariants = append(g.bounceVariants, v)
+	}
+	slog.Debug("bounce pitch variants ready", "count", len(g.bounceVariants))
+}
+
+// bounceChunk is the chunk to play for a bounce: a random pitch variant when
+// pitchVariation is on and they could be made. speedReactiveAudio picks them
+// by speed instead, in playBounceSound.
+func (g *Game) bounceChunk() *mix.Chunk {
+	if g.cfg.PitchVariation && len(g.bounceVariants) > 0 {
+		return g.bounceVariants[g.rng.Intn(len(g.bounceVariants))]
+	}
+	return g.assets.Sound(soundBounce)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	presetDir = "presets"
+
+	// defaultPresetName is what the menu saves to before any preset is
+	// loaded.
+	defaultPresetName = "custom"
+)
+
+func presetPath(name string) (string, error) {
+	if name == "" || strings.HasPrefix(name, ".") || filepath.Base(name) != name {
+		return "", fmt.Errorf("invalid preset name %q", name)
+	}
+	return filepath.Join(presetDir, name+".json"), nil
+}
+
+// ListPresets returns the names of the saved presets, sorted.
+func ListPresets() []string {
+	entries, err := os.ReadDir(presetDir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("could not list presets", "err", err)
+		}
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SavePreset writes the current settings, including anything toggled at
+// runtime, to the named preset.
+func (g *Game) SavePreset(name string) error {
+	path, err := presetPath(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(g.currentConfig(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error encoding preset: %v", err)
+	}
+	if err := os.MkdirAll(presetDir, 0o755); err != nil {
+		return fmt.Errorf("Error creating preset directory: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("Error saving preset: %v", err)
+	}
+	g.preset = name
+	slog.Info("preset saved", "name", name, "path", path)
+	return nil
+}
+
+// LoadPreset reads and validates the named preset and applies it the same
+// way a hot-reloaded config is applied.
+func (g *Game) LoadPreset(name string) error {
+	path, err := presetPath(name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Error reading preset: %v", err)
+	}
+	cfg, err := parseConfig(path, data)
+	if err != nil {
+		return err
+	}
+
+	// Diff against what is actually live so runtime toggles are switched
+	// to the preset's values too.
+	*g.cfg = *g.currentConfig()
+	g.applyConfig(cfg)
+	g.preset = name
+	slog.Info("preset loaded", "name", name)
+	return nil
+}
+
+// resetSettings puts the settings back to those of the config file and
+// command line, undoing whatever was changed while running from the menu,
+// a preset or the keys, and logs each one that changes. Nothing is
+// reloaded, and the scene carries on as it is.
+func (g *Game) resetSettings() {
+	base := *g.baseConfig
+	slog.Info("resetting settings")
+	*g.cfg = *g.currentConfig()
+	g.applyConfig(&base)
+	g.preset = ""
+
+	// Toggles the config has no setting for start out off.
+	resetToggle("showFPS", &g.showFPS, false)
+	resetToggle("showDebug", &g.showDebug, false)
+	resetToggle("clipScene", &g.clipScene, false)
+	resetToggle("freeCamera", &g.freeCamera, false)
+	if g.muted != g.opts.Mute {
+		slog.Info("setting changed", "setting", "muted", "old", g.muted, "new", g.opts.Mute)
+		g.toggleMute()
+	}
+	flags, _ := parseWindowFlags(base.WindowFlags)
+	if fullscreen := flags&(sdl.WINDOW_FULLSCREEN|sdl.WINDOW_FULLSCREEN_DESKTOP) != 0; g.isFullscreen != fullscreen {
+		slog.Info("setting changed", "setting", "fullscreen", "old", g.isFullscreen, "new", fullscreen)
+		g.toggleFullscreen()
+	}
+}
+
+func resetToggle(name string, v *bool, to bool) {
+	if *v != to {
+		slog.Info("setting changed", "setting", name, "old", *v, "new", to)
+		*v = to
+	}
+}
+
+// currentConfig returns a copy of the config with the settings that can be
+// toggled while running set to their live values.
+func (g *Game) currentConfig() *Config {
+	cfg := *g.cfg
+	cfg.TintAnimation = g.tintAnimation
+	cfg.SpriteJitter = g.jitter
+	cfg.Flocking = g.flocking
+	cfg.ColorCycling = g.colorCycling
+	cfg.ShowInputs = g.showInputs
+	cfg.DebugWindow = g.debugWindow != nil
+	cfg.SplitScreen = g.splitScreen
+	cfg.HudBlur = g.hudBlur
+	cfg.BPMPulse = g.bpmPulse
+	cfg.Afterimage = g.afterimage
+	cfg.TextTrail = g.textTrail
+	cfg.Volume = g.savedVolume()
+	cfg.AlwaysOnTop = g.alwaysOnTop
+	cfg.FocusMode = g.focusMode
+	return &cfg
+}
+
+// presetSaveName is the preset the menu saves to: the one last loaded or
+// saved, or the default name.
+func (g *Game) presetSaveName() string {
+	if g.preset == "" {
+		return defaultPresetName
+	}
+	return g.preset
+}
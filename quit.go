@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const quitPadding = 12
+
+// requestQuit quits, or with confirmQuit on asks first.
+func (g *Game) requestQuit() {
+	if !g.cfg.ConfirmQuit {
+		g.quit = true
+		return
+	}
+	g.confirmingQuit = true
+	g.quitAskedAt = sdl.GetTicks()
+}
+
+// handleQuitConfirmKey answers the quit question: Y quits, N or Escape
+// goes back to the game and other keys are ignored.
+func (g *Game) handleQuitConfirmKey(key sdl.Keycode) {
+	switch key {
+	case sdl.K_y:
+		g.quit = true
+	case sdl.K_n, sdl.K_ESCAPE:
+		g.confirmingQuit = false
+	}
+}
+
+// quitConfirmRemaining is how long until the quit question is dismissed,
+// in ms, and whether it times out at all.
+func (g *Game) quitConfirmRemaining() (uint32, bool) {
+	timeout := uint32(g.cfg.QuitConfirmTimeoutSeconds) * 1000
+	if timeout == 0 {
+		return 0, false
+	}
+	elapsed := sdl.GetTicks() - g.quitAskedAt
+	if elapsed >= timeout {
+		return 0, true
+	}
+	return timeout - elapsed, true
+}
+
+// updateQuitConfirm dismisses the quit question once it times out.
+func (g *Game) updateQuitConfirm() {
+	if !g.confirmingQuit {
+		return
+	}
+	if left, ok := g.quitConfirmRemaining(); ok && left == 0 {
+		g.confirmingQuit = false
+	}
+}
+
+// renderQuitConfirm asks whether to quit in a panel in the middle of the
+// window, with the time left before it goes away underneath.
+func (g *Game) renderQuitConfirm() {
+	const question = "Quit? Y / N"
+	font := g.font(fontUI)
+	if font == nil {
+		return
+	}
+	w, lineHeight, err := font.SizeUTF8(question)
+	if err != nil {
+		return
+	}
+	countdown := ""
+	if left, ok := g.quitConfirmRemaining(); ok {
+		countdown = fmt.Sprintf("Closing in %ds", (left+999)/1000)
+		if cw, _, err := font.SizeUTF8(countdown); err == nil {
+			w = max(w, cw)
+		}
+	}
+	lines := int32(1)
+	if countdown != "" {
+		lines = 2
+	}
+	panel := sdl.Rect{W: int32(w) + 2*quitPadding, H: lines*int32(lineHeight) + 2*quitPadding}
+	panel.X, panel.Y = (g.width-panel.W)/2, (g.height-panel.H)/2
+	g.drawPanel(panel, 200)
+	g.drawText(fontUI, question, sdl.Color{R: 255, G: 255, B: 255, A: 255}, panel.X+quitPadding, panel.Y+quitPadding)
+	g.drawText(fontUI, countdown, sdl.Color{R: 160, G: 160, B: 160, A: 255}, panel.X+quitPadding, panel.Y+quitPadding+int32(lineHeight))
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// RateLimiter permits an action at most once per interval. The clock defaults
+// to sdl.GetTicks and can be swapped out through now.
+type RateLimiter struct {
+	interval uint32
+	last     uint32
+	allowed  bool
+	now      func() uint32
+}
+
+func NewRateLimiter(interval time.Duration) *RateLimiter {
+	return &RateLimiter{interval: uint32(interval.Milliseconds()), now: sdl.GetTicks}
+}
+
+// Allow reports whether the action may happen now, and if so starts a new
+// interval.
+func (r *RateLimiter) Allow() bool {
+	now := r.now()
+	if r.allowed && now-r.last < r.interval {
+		return false
+	}
+	r.last = now
+	r.allowed = true
+	return true
+}
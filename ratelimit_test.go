@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// fakeClock is a RateLimiter clock that only moves when told to.
+type fakeClock struct {
+	ticks uint32
+}
+
+func (c *fakeClock) now() uint32 { return c.ticks }
+
+func newTestLimiter(interval time.Duration, start uint32) (*RateLimiter, *fakeClock) {
+	c := &fakeClock{ticks: start}
+	r := NewRateLimiter(interval)
+	r.now = c.now
+	return r, c
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	r, c := newTestLimiter(100*time.Millisecond, 1000)
+	steps := []struct {
+		ticks uint32
+		want  bool
+	}{
+		{1000, true},  // the first call is always allowed
+		{1000, false}, // again at once
+		{1099, false}, // just before the interval is up
+		{1100, true},  // exactly when it is up
+		{1150, false},
+		{1500, true}, // long after
+		{1599, false},
+	}
+	for _, s := range steps {
+		c.ticks = s.ticks
+		if got := r.Allow(); got != s.want {
+			t.Errorf("Allow() at %d = %v, want %v", s.ticks, got, s.want)
+		}
+	}
+}
+
+func TestRateLimiterFirstCallAtZero(t *testing.T) {
+	// A last of 0 must not be mistaken for a call made at tick 0.
+	r, c := newTestLimiter(100*time.Millisecond, 0)
+	if !r.Allow() {
+		t.Fatal("first Allow() at tick 0 was denied")
+	}
+	c.ticks = 50
+	if r.Allow() {
+		t.Error("Allow() at 50 after 0 was allowed")
+	}
+}
+
+func TestRateLimiterWraparound(t *testing.T) {
+	// GetTicks wraps after about 49 days; the elapsed time is still right
+	// across the wrap.
+	r, c := newTestLimiter(100*time.Millisecond, math.MaxUint32-40)
+	if !r.Allow() {
+		t.Fatal("first Allow() was denied")
+	}
+	c.ticks = 20 // 61ms later, past the wrap
+	if r.Allow() {
+		t.Error("Allow() 61ms later across the wrap was allowed")
+	}
+	c.ticks = 59 // exactly 100ms later
+	if !r.Allow() {
+		t.Error("Allow() 100ms later across the wrap was denied")
+	}
+}
+
+func TestRateLimiterZeroInterval(t *testing.T) {
+	r, _ := newTestLimiter(0, 1000)
+	for i := 0; i < 3; i++ {
+		if !r.Allow() {
+			t.Fatalf("Allow() call %d with no interval was denied", i)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var errNotAccelerated = errors.New("only software rendering is available")
+
+// createRenderer creates the main window's renderer and checks it.
+func (g *Game) createRenderer() error {
+	var err error
+	g.renderer, err = g.newRenderer(g.window)
+	if err != nil {
+		return err
+	}
+	return g.checkRenderer()
+}
+
+// newRenderer creates a renderer for window, falling back to software
+// rendering with a warning when no accelerated driver works, unless
+// -require-accelerated was given.
+func (g *Game) newRenderer(window *sdl.Window) (*sdl.Renderer, error) {
+	var r *sdl.Renderer
+	var err error
+	if g.opts.Software {
+		r, err = g.createVSyncRenderer(window, sdl.RENDERER_SOFTWARE)
+	} else {
+		r, err = g.createVSyncRenderer(window, sdl.RENDERER_ACCELERATED)
+		if err != nil && !g.opts.RequireAccelerated {
+			slog.Warn("accelerated renderer unavailable, falling back to software", "err", err)
+			r, err = g.createVSyncRenderer(window, sdl.RENDERER_SOFTWARE)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error creating renderer: %v", err)
+	}
+	return r, nil
+}
+
+// createVSyncRenderer creates a renderer with flags, presenting with vsync
+// if the pacing mode asks for it. Without a driver that supports vsync it
+// warns and makes do without.
+func (g *Game) createVSyncRenderer(window *sdl.Window, flags uint32) (*sdl.Renderer, error) {
+	if g.cfg.PacingMode != pacingVSync {
+		return sdl.CreateRenderer(window, -1, flags)
+	}
+	r, err := sdl.CreateRenderer(window, -1, flags|sdl.RENDERER_PRESENTVSYNC)
+	if err == nil {
+		return r, nil
+	}
+	slog.Warn("no renderer with vsync, presenting without it", "err", err)
+	return sdl.CreateRenderer(window, -1, flags)
+}
+
+// checkRenderer logs the video and render drivers in use and verifies the
+// renderer is hardware accelerated when that is required.
+func (g *Game) checkRenderer() error {
+	driver, err := sdl.GetCurrentVideoDriver()
+	if err != nil {
+		slog.Warn("could not query video driver", "err", err)
+	}
+	info, err := g.renderer.GetInfo()
+	if err != nil {
+		return fmt.Errorf("Error querying renderer: %v", err)
+	}
+	accelerated := info.Flags&sdl.RENDERER_ACCELERATED != 0
+	slog.Info("renderer ready",
+		"videoDriver", driver,
+		"renderer", info.Name,
+		"accelerated", accelerated,
+		"vsync", info.Flags&sdl.RENDERER_PRESENTVSYNC != 0,
+		"maxTexture", fmt.Sprintf("%dx%d", info.MaxTextureWidth, info.MaxTextureHeight))
+
+	if strings.HasPrefix(info.Name, "opengl") {
+		major, _ := sdl.GLGetAttribute(sdl.GL_CONTEXT_MAJOR_VERSION)
+		minor, _ := sdl.GLGetAttribute(sdl.GL_CONTEXT_MINOR_VERSION)
+		slog.Info("OpenGL context", "version", fmt.Sprintf("%d.%d", major, minor))
+	}
+
+	if !accelerated {
+		if g.opts.RequireAccelerated {
+			return fmt.Errorf("Error checking renderer %q: %w", info.Name, errNotAccelerated)
+		}
+		if !g.opts.Software {
+			slog.Warn("renderer is not hardware accelerated, expect poor performance", "renderer", info.Name)
+		}
+	}
+	return nil
+}
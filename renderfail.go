@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// renderFailureLimit is how many frames in a row may fail to draw before
+// Run gives up. A frame failing now and then, e.g. while the renderer is
+// being reset, is only logged.
+const renderFailureLimit = 30
+
+// checkRender records err, from doing what, if it is the frame's first
+// rendering error.
+func (g *Game) checkRender(what string, err error) {
+	if err != nil && g.frameErr == nil {
+		g.frameErr = fmt.Errorf("Error %s: %v", what, err)
+	}
+}
+
+// frameFailed ends the frame's error checking. It counts the frames in a
+// row that failed to draw, logging the first, and returns the error once
+// renderFailureLimit of them have.
+func (g *Game) frameFailed() error {
+	err := g.frameErr
+	g.frameErr = nil
+	if err == nil {
+		if g.renderFailures > 0 {
+			slog.Info("frames draw again", "failed", g.renderFailures)
+		}
+		g.renderFailures = 0
+		return nil
+	}
+	g.renderFailures++
+	if g.renderFailures == 1 {
+		slog.Warn("frame failed to draw", "err", err)
+	}
+	if g.renderFailures >= renderFailureLimit {
+		return fmt.Errorf("%v (%d frames in a row)", err, g.renderFailures)
+	}
+	return nil
+}
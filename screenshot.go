@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/veandco/go-sdl2/img"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// screenshotRequest is a capture queued from the event loop. Captures are
+// taken once the scene is drawn, before overlays and before Present, since
+// the back buffer contents are undefined after presenting. An empty rect
+// captures the whole frame.
+type screenshotRequest struct {
+	rect sdl.Rect
+	path string
+}
+
+func (g *Game) queueScreenshot(rect sdl.Rect, prefix string) {
+	path := prefix + "-" + time.Now().Format("20060102-150405") + ".png"
+	g.screenshots = append(g.screenshots, screenshotRequest{rect: rect, path: path})
+}
+
+func (g *Game) takeScreenshots() {
+	for _, s := range g.screenshots {
+		var err error
+		path := s.path
+		if s.rect.Empty() {
+			path, err = g.saveScreenshot(s.path)
+		} else {
+			path, err = g.screenshotRegion(s.rect, s.path)
+		}
+		if err != nil {
+			slog.Error("screenshot failed", "path", s.path, "err", err)
+			continue
+		}
+		slog.Info("saved screenshot", "path", path)
+	}
+	g.screenshots = g.screenshots[:0]
+}
+
+// saveScreenshot saves the whole of the current frame as a PNG, returning
+// where it was saved, as screenshotRegion does.
+func (g *Game) saveScreenshot(path string) (string, error) {
+	w, h, err := g.renderer.GetOutputSize()
+	if err != nil {
+		return "", fmt.Errorf("Error getting output size: %v", err)
+	}
+	return g.screenshotRegion(sdl.Rect{W: w, H: h}, path)
+}
+
+// screenshotRegion saves the part of the current frame inside rect as a PNG,
+// or as a BMP next to it if that fails, returning the path it was saved to.
+// The rect is clamped to the renderer output.
+func (g *Game) screenshotRegion(rect sdl.Rect, path string) (string, error) {
+	w, h, err := g.renderer.GetOutputSize()
+	if err != nil {
+		return "", fmt.Errorf("Error getting output size: %v", err)
+	}
+	bounds := sdl.Rect{X: 0, Y: 0, W: w, H: h}
+	region, ok := rect.Intersect(&bounds)
+	if !ok {
+		return "", fmt.Errorf("region %+v is outside the %dx%d output", rect, w, h)
+	}
+	if region != rect {
+		slog.Debug("screenshot region clamped", "requested", rect, "clamped", region)
+	}
+
+	surface, err := sdl.CreateRGBSurfaceWithFormat(0, region.W, region.H, 32, sdl.PIXELFORMAT_ARGB8888)
+	if err != nil {
+		return "", fmt.Errorf("Error creating screenshot surface: %v", err)
+	}
+	defer surface.Free()
+
+	// Some renderers can't read back what they drew.
+	err = g.renderer.ReadPixels(&region, surface.Format.Format, surface.Data(), int(surface.Pitch))
+	if err != nil {
+		return "", fmt.Errorf("Error reading pixels, the renderer may not support it: %v", err)
+	}
+	err = img.SavePNG(surface, path)
+	if err == nil {
+		return path, nil
+	}
+	bmp := strings.TrimSuffix(path, ".png") + ".bmp"
+	slog.Warn("could not save screenshot as PNG, trying BMP", "path", path, "err", err)
+	if err := surface.SaveBMP(bmp); err != nil {
+		return "", fmt.Errorf("Error saving %s: %v", bmp, err)
+	}
+	return bmp, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Patterns spawned sprites are laid out in: each anywhere in the window, or
+// around the player in a grid, a ring or a loose cluster.
+const (
+	spawnRandom  = "random"
+	spawnGrid    = "grid"
+	spawnCircle  = "circle"
+	spawnCluster = "cluster"
+)
+
+const spriteCountPadding = 8
+
+// spawnSpacing is the distance between the centers of neighboring sprites in
+// a grid or ring, leaving a gap between them.
+const spawnSpacing = spawnSize * 1.25
+
+func validateSpawnPattern(p string) error {
+	switch p {
+	case spawnRandom, spawnGrid, spawnCircle, spawnCluster:
+		return nil
+	}
+	return fmt.Errorf("spawnPattern must be %q, %q, %q or %q, got %q", spawnRandom, spawnGrid, spawnCircle, spawnCluster, p)
+}
+
+// spawnPattern returns where the centers of spawnCount sprites go in the
+// configured pattern around origin.
+func (g *Game) spawnPattern(origin Vec2) []Vec2 {
+	n := g.cfg.SpawnCount
+	points := make([]Vec2, n)
+	switch g.cfg.SpawnPattern {
+	case spawnGrid:
+		cols := int(math.Ceil(math.Sqrt(float64(n))))
+		rows := (n + cols - 1) / cols
+		corner := origin.Sub(Vec2{X: float64(cols-1) * spawnSpacing / 2, Y: float64(rows-1) * spawnSpacing / 2})
+		for i := range points {
+			points[i] = corner.Add(Vec2{X: float64(i%cols) * spawnSpacing, Y: float64(i/cols) * spawnSpacing})
+		}
+	case spawnCircle:
+		// Big enough around for the sprites not to overlap.
+		radius := max(spawnSize, float64(n)*spawnSpacing/(2*math.Pi))
+		for i := range points {
+			angle := float64(i) * 2 * math.Pi / float64(n)
+			points[i] = origin.Add(Vec2{X: math.Cos(angle), Y: math.Sin(angle)}.Scale(radius))
+		}
+	case spawnCluster:
+		// Spread evenly over a disc that grows with the count.
+		radius := spawnSize * math.Sqrt(float64(n))
+		for i := range points {
+			angle := g.rng.Float64() * 2 * math.Pi
+			r := radius * math.Sqrt(g.rng.Float64())
+			points[i] = origin.Add(Vec2{X: math.Cos(angle), Y: math.Sin(angle)}.Scale(r))
+		}
+	default:
+		for i := range points {
+			points[i] = Vec2{
+				X: spawnSize/2 + g.rng.Float64()*float64(g.width-spawnSize),
+				Y: spawnSize/2 + g.rng.Float64()*float64(g.height-spawnSize),
+			}
+		}
+	}
+	return points
+}
+
+// spawnGroup spawns sprites in the configured pattern around the player. No
+// more are spawned than fit under maxSprites, making room for them by
+// removing the oldest.
+func (g *Game) spawnGroup() {
+	points := g.spawnPattern(g.player.box.Center())
+	points = points[:min(len(points), g.cfg.MaxSprites-1)]
+	g.evictSprites(g.cfg.MaxSprites - len(points))
+	var area AABB
+	for _, p := range points {
+		g.spawnSpriteAt(p)
+		area = area.Union(g.sprites[len(g.sprites)-1].box)
+	}
+	slog.Debug("sprites spawned", "pattern", g.cfg.SpawnPattern, "spawned", len(points), "count", len(g.sprites)-1, "area", area)
+}
+
+func (g *Game) spriteCountVisible() bool {
+	return g.state == g.playing && len(g.sprites) > 1
+}
+
+// renderSpriteCount shows how many sprites there are, counting the player,
+// out of maxSprites in a panel in the bottom right corner.
+func (g *Game) renderSpriteCount() {
+	font := g.font(fontUI)
+	if font == nil {
+		return
+	}
+	label := fmt.Sprintf("Sprites: %d/%d", len(g.sprites), g.cfg.MaxSprites)
+	w, _, err := font.SizeUTF8(label)
+	if err != nil {
+		return
+	}
+	panel := sdl.Rect{W: int32(w) + 2*spriteCountPadding, H: g.lineHeight(fontUI) + 2*spriteCountPadding}
+	panel.X, panel.Y = g.width-panel.W-spriteCountPadding, g.height-panel.H-spriteCountPadding
+	g.drawPanel(panel, 160)
+	g.drawText(fontUI, label, sdl.Color{R: 255, G: 255, B: 255, A: 255}, panel.X+spriteCountPadding, panel.Y+spriteCountPadding)
+}
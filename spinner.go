@@ -0,0 +1,57 @@
+package main
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// spinnerDots is how many dots make up the loading spinner's ring, and
+// spinnerTurnSeconds how long the bright dot takes to go round it once.
+const (
+	spinnerDots        = 12
+	spinnerTurnSeconds = 1.0
+)
+
+// drawSpinner draws a ring of dots around cx, cy, radius pixels out, in
+// SpinnerColor. The brightest dot goes round with the time since SDL
+// started and the ones behind it fade out, so drawing it every frame, or
+// between steps of slow work, shows that something is still going on. The
+// draw color and blend mode are kept.
+func (g *Game) drawSpinner(cx, cy, radius int32) {
+	pr, pg, pb, pa, _ := g.renderer.GetDrawColor()
+	defer g.renderer.SetDrawColor(pr, pg, pb, pa)
+	var mode sdl.BlendMode
+	g.renderer.GetDrawBlendMode(&mode)
+	defer g.renderer.SetDrawBlendMode(mode)
+	g.renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+
+	c := g.cfg.SpinnerColor
+	size := max(2, radius/4)
+	turn := math.Mod(float64(sdl.GetTicks())/1000/spinnerTurnSeconds, 1)
+	head := int(turn * spinnerDots)
+	for i := 0; i < spinnerDots; i++ {
+		angle := 2 * math.Pi * float64(i) / spinnerDots
+		x := cx + int32(math.Round(float64(radius)*math.Sin(angle)))
+		y := cy - int32(math.Round(float64(radius)*math.Cos(angle)))
+		behind := (head - i + spinnerDots) % spinnerDots
+		alpha := float64(c.A) * (1 - float64(behind)/spinnerDots)
+		g.renderer.SetDrawColor(c.R, c.G, c.B, uint8(alpha))
+		g.renderer.FillRect(&sdl.Rect{X: x - size/2, Y: y - size/2, W: size, H: size})
+	}
+}
+
+// renderLoading shows a frame with only the spinner in the middle, between
+// the steps of loading the assets in Init, before there is a scene to draw.
+// It does nothing when SpinnerSize is 0. Errors drawing it are ignored, as
+// the first real frame will report them.
+func (g *Game) renderLoading() {
+	if g.cfg.SpinnerSize <= 0 {
+		return
+	}
+	c := g.bgColor
+	g.renderer.SetDrawColor(c.R, c.G, c.B, c.A)
+	g.renderer.Clear()
+	g.drawSpinner(g.width/2, g.height/2, g.cfg.SpinnerSize)
+	g.renderer.Present()
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"log/slog"
+	"math"
+	"slices"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	spawnSize     = 48
+	spawnMinSpeed = 50.0
+	spawnMaxSpeed = 150.0
+
+	// tintStep is how much a key press changes a channel of the player's
+	// tint.
+	tintStep = 16
+)
+
+// collideAll puts a sprite in every collision group, or makes it collide
+// with every group.
+const collideAll = ^uint32(0)
+
+// untinted is the color mod that draws a texture as it is.
+var untinted = sdl.Color{R: 255, G: 255, B: 255, A: 255}
+
+// Sprite is a textured box in the scene. The player sprite is moved with the
+// keyboard, spawned sprites move by their own velocity.
+type Sprite struct {
+	texture *sdl.Texture
+	box     AABB
+	vel     Vec2      // pixels per second
+	tint    sdl.Color // color mod the texture is drawn with
+
+	// lifetime is how many seconds the sprite has left before it is
+	// removed, out of the lifespan it was spawned with. A lifespan of 0
+	// keeps it forever.
+	lifetime float64
+	lifespan float64
+
+	// collisionLayer has a bit set for each group the sprite belongs to
+	// and collisionMask one for each group it collides with. Both default to
+	// collideAll so that everything collides with everything.
+	collisionLayer uint32
+	collisionMask  uint32
+
+	// onUpdate, when set, is called every frame with the frame time in
+	// seconds, before the sprite is moved and kept inside the window, for
+	// behavior of its own such as speeding up over time.
+	onUpdate func(s *Sprite, dt float64)
+}
+
+// update runs the sprite's onUpdate callback, if it has one.
+func (s *Sprite) update(dt float64) {
+	if s.onUpdate != nil {
+		s.onUpdate(s, dt)
+	}
+}
+
+// expired reports whether the sprite has outlived its lifespan.
+func (s *Sprite) expired() bool {
+	return s.lifespan > 0 && s.lifetime <= 0
+}
+
+// alpha is how opaque the sprite is drawn: fully, unless it is in the last
+// fadeOut fraction of its lifespan, over which it fades out.
+func (s *Sprite) alpha(fadeOut float64) uint8 {
+	if s.lifespan == 0 || fadeOut == 0 {
+		return 255
+	}
+	left := max(s.lifetime, 0) / s.lifespan
+	return uint8(255 * min(left/fadeOut, 1))
+}
+
+// canCollide reports whether the sprites' collision groups let them collide:
+// each has to be in a group the other collides with.
+func (s *Sprite) canCollide(o *Sprite) bool {
+	return s.collisionLayer&o.collisionMask != 0 && o.collisionLayer&s.collisionMask != 0
+}
+
+// Collides reports whether the sprites collide: whether their collision
+// groups allow it and, only then, whether their boxes overlap.
+func (s *Sprite) Collides(o *Sprite) bool {
+	return s.canCollide(o) && s.box.Intersects(o.box)
+}
+
+// adjustTint changes channel 0 (red), 1 (green) or 2 (blue) of the tint by
+// delta, keeping it within 0-255.
+func (s *Sprite) adjustTint(channel, delta int) {
+	c := [...]*uint8{&s.tint.R, &s.tint.G, &s.tint.B}[channel]
+	*c = uint8(max(0, min(int(*c)+delta, 255)))
+}
+
+// renderSprites draws every sprite as seen through cam, the player last so it
+// stays on top of the ones it leads. They all show the same frame of the
+// sprite sheet.
+func (g *Game) renderSprites(cam Camera) {
+	for _, s := range g.sprites[1:] {
+		s.texture.SetColorMod(s.tint.R, s.tint.G, s.tint.B)
+		s.texture.SetAlphaMod(s.alpha(g.cfg.SpriteFadeOut))
+		dst := cam.Apply(s.box.FRect())
+		g.checkRender("drawing a sprite", g.renderer.CopyF(s.texture, g.spriteAnim.CurrentSrcRect(), &dst))
+	}
+	g.player.texture.SetColorMod(g.spriteColorMod())
+	g.player.texture.SetAlphaMod(255)
+	dst := cam.Apply(g.spriteRenderRect())
+	g.checkRender("drawing the sprite", g.renderer.CopyF(g.player.texture, g.spriteAnim.CurrentSrcRect(), &dst))
+}
+
+// spawnSprite adds a sprite at a random place in the window heading in a
+// random direction.
+func (g *Game) spawnSprite() {
+	g.evictSprites(g.cfg.MaxSprites - 1)
+	g.spawnSpriteAt(Vec2{
+		X: spawnSize/2 + g.rng.Float64()*float64(g.width-spawnSize),
+		Y: spawnSize/2 + g.rng.Float64()*float64(g.height-spawnSize),
+	})
+	slog.Debug("sprite spawned", "count", len(g.sprites)-1)
+}
+
+// spawnSpriteAt adds a sprite centered on p, moved inside the window if need
+// be, heading in a random direction, to live for spriteLifetime. The caller
+// makes room for it.
+func (g *Game) spawnSpriteAt(p Vec2) {
+	angle := g.rng.Float64() * 2 * math.Pi
+	speed := spawnMinSpeed + g.rng.Float64()*(spawnMaxSpeed-spawnMinSpeed)
+	s := &Sprite{
+		texture: g.sprite,
+		box: AABB{
+			X: clampf(p.X-spawnSize/2, 0, float64(g.width-spawnSize)),
+			Y: clampf(p.Y-spawnSize/2, 0, float64(g.height-spawnSize)),
+			W: spawnSize,
+			H: spawnSize,
+		},
+		vel:            Vec2{X: math.Cos(angle) * speed, Y: math.Sin(angle) * speed},
+		tint:           untinted,
+		lifetime:       g.cfg.SpriteLifetime,
+		lifespan:       g.cfg.SpriteLifetime,
+		collisionLayer: collideAll,
+		collisionMask:  collideAll,
+	}
+	g.sprites = append(g.sprites, s)
+}
+
+// evictSprites removes the oldest spawned sprites until at most n sprites,
+// counting the player, are left. The player is never removed.
+func (g *Game) evictSprites(n int) {
+	extra := len(g.sprites) - max(n, 1)
+	if extra <= 0 {
+		return
+	}
+	g.sprites = slices.Delete(g.sprites, 1, 1+extra)
+	slog.Debug("sprites evicted", "evicted", extra, "limit", g.cfg.MaxSprites)
+}
+
+// updateSprites moves the spawned sprites by dt seconds' worth of their
+// velocity, steering them with the flocking rules first when flocking is on,
+// and removes those whose lifetime ran out. The texture they share stays
+// loaded for the player and the next ones spawned.
+func (g *Game) updateSprites(dt float64) {
+	spawned := g.sprites[1:]
+	if len(spawned) == 0 {
+		return
+	}
+	if g.flocking {
+		g.flock(spawned, dt)
+	}
+	for _, s := range spawned {
+		s.update(dt)
+		s.box.X += s.vel.X * dt
+		s.box.Y += s.vel.Y * dt
+		bounceInside(&s.box.X, &s.vel.X, s.box.W, float64(g.width))
+		bounceInside(&s.box.Y, &s.vel.Y, s.box.H, float64(g.height))
+		s.lifetime -= dt
+	}
+	n := len(g.sprites)
+	g.sprites = slices.DeleteFunc(g.sprites, (*Sprite).expired)
+	if n > len(g.sprites) {
+		slog.Debug("sprites expired", "expired", n-len(g.sprites), "count", len(g.sprites)-1)
+	}
+}
+
+// bounceInside keeps a box of the given size within [0, length) along one
+// axis, reflecting its velocity when it runs into either end.
+func bounceInside(pos, vel *float64, size, length float64) {
+	if *pos < 0 {
+		*pos = -*pos
+		*vel = math.Abs(*vel)
+	} else if *pos+size > length {
+		*pos = 2*(length-size) - *pos
+		*vel = -math.Abs(*vel)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestSpriteCollides(t *testing.T) {
+	const (
+		players = 1 << iota
+		enemies
+		pickups
+	)
+	box := AABB{W: 10, H: 10}
+	apart := AABB{X: 20, W: 10, H: 10}
+	tests := []struct {
+		name          string
+		aLayer, aMask uint32
+		bLayer, bMask uint32
+		bBox          AABB
+		want          bool
+	}{
+		{"default groups", collideAll, collideAll, collideAll, collideAll, box, true},
+		{"default groups apart", collideAll, collideAll, collideAll, collideAll, apart, false},
+		{"same layer and mask", enemies, enemies, enemies, enemies, box, true},
+		{"same layer masked out", enemies, players, enemies, players, box, false},
+		{"each other's groups", players, enemies, enemies, players, box, true},
+		{"groups allow it but apart", players, enemies, enemies, players, apart, false},
+		{"only a collides with b", players, enemies, enemies, pickups, box, false},
+		{"only b collides with a", players, pickups, enemies, players, box, false},
+		{"no mask", players, 0, enemies, collideAll, box, false},
+		{"no layer", 0, collideAll, enemies, collideAll, box, false},
+		{"one shared bit", players | pickups, enemies, enemies, pickups, box, true},
+	}
+	for _, tt := range tests {
+		a := &Sprite{box: box, collisionLayer: tt.aLayer, collisionMask: tt.aMask}
+		b := &Sprite{box: tt.bBox, collisionLayer: tt.bLayer, collisionMask: tt.bMask}
+		if got := a.Collides(b); got != tt.want {
+			t.Errorf("%s: a.Collides(b) = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := b.Collides(a); got != tt.want {
+			t.Errorf("%s: b.Collides(a) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// squashWobbles is how many half swings between squashed and stretched a
+// squash makes before it settles.
+const squashWobbles = 3
+
+// Squash is a squash and stretch of something drawn, scaling it about its
+// center without moving it, e.g. when it bounces off a wall. At rest both
+// scales are 1.
+type Squash struct {
+	scaleX, scaleY float64
+	tween          *Tween
+}
+
+func newSquash() Squash {
+	return Squash{scaleX: 1, scaleY: 1}
+}
+
+// Apply scales r about its center.
+func (s *Squash) Apply(r sdl.FRect) sdl.FRect {
+	w, h := r.W*float32(s.scaleX), r.H*float32(s.scaleY)
+	return sdl.FRect{X: r.X + (r.W-w)/2, Y: r.Y + (r.H-h)/2, W: w, H: h}
+}
+
+// squash squashes s along the axis of an impact, horizontal or vertical,
+// and stretches it along the other by squashAmount, then springs it back
+// over squashSeconds, wobbling less and less. A squash already running
+// jumps to its end first.
+func (g *Game) squash(s *Squash, horizontal bool) {
+	if g.cfg.SquashAmount == 0 || g.cfg.ReducedMotion {
+		return
+	}
+	if s.tween != nil {
+		s.tween.Finish()
+	}
+	amount := g.cfg.SquashAmount
+	s.tween = NewTween(g.cfg.SquashSeconds, easeLinear, func(p float64) {
+		a := amount * (1 - p) * math.Cos(squashWobbles*math.Pi*p)
+		if p == 1 {
+			a = 0 // exactly back at rest
+		}
+		if horizontal {
+			s.scaleX, s.scaleY = 1-a, 1+a
+		} else {
+			s.scaleX, s.scaleY = 1+a, 1-a
+		}
+	})
+	g.tweens = append(g.tweens, s.tween)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const startLabel = "Press Enter to start"
+
+// GameState is one screen of the game, such as the title screen. The
+// current state gets the events the game as a whole doesn't handle, and is
+// updated and drawn every frame under the overlays.
+type GameState interface {
+	HandleEvent(event sdl.Event)
+	Update(dt float64)
+	Render(r *sdl.Renderer)
+}
+
+// setState switches to another state from the next event on.
+func (g *Game) setState(s GameState) {
+	if s == g.state {
+		return
+	}
+	slog.Debug("game state changed", "from", stateName(g.state), "to", stateName(s))
+	g.state = s
+}
+
+func stateName(s GameState) string {
+	switch s.(type) {
+	case *MenuState:
+		return "menu"
+	case *PlayingState:
+		return "playing"
+	}
+	return "none"
+}
+
+// MenuState is the title screen the game starts on, unless titleScreen is
+// off. Enter starts playing, and Escape or the quit key quits from it.
+type MenuState struct {
+	g *Game
+}
+
+func (s *MenuState) HandleEvent(event sdl.Event) {
+	g := s.g
+	switch e := event.(type) {
+	case *sdl.KeyboardEvent:
+		if e.Type != sdl.KEYDOWN {
+			return
+		}
+		switch {
+		case e.Keysym.Sym == sdl.K_RETURN || e.Keysym.Sym == sdl.K_KP_ENTER:
+			g.setState(g.playing)
+		case g.isQuitKey(e.Keysym.Sym):
+			g.requestQuit()
+		}
+	case *sdl.ControllerButtonEvent:
+		if e.Type != sdl.CONTROLLERBUTTONDOWN {
+			return
+		}
+		switch g.boundAction(sdl.GameControllerButton(e.Button)) {
+		case bindAction, bindPause:
+			g.setState(g.playing)
+		case bindBack:
+			g.requestQuit()
+		}
+	}
+}
+
+// Update does nothing: the scene stays still behind the title screen.
+func (s *MenuState) Update(dt float64) {}
+
+// Render draws the background with the title in the middle and how to
+// start underneath.
+func (s *MenuState) Render(r *sdl.Renderer) {
+	g := s.g
+	g.clear()
+	g.renderBackground(Camera{})
+	y := g.height / 2
+	if g.text != nil {
+		_, _, w, h, err := g.text.Query()
+		if err == nil {
+			g.text.SetAlphaMod(255)
+			dst := sdl.Rect{X: (g.width - w) / 2, Y: (g.height - h) / 2, W: w, H: h}
+			g.checkRender("drawing the title", r.Copy(g.text, nil, &dst))
+			y = dst.Y + h + pausePadding
+		}
+	}
+	font := g.font(fontUI)
+	if font == nil {
+		return
+	}
+	w, _, err := font.SizeUTF8(startLabel)
+	if err != nil {
+		return
+	}
+	g.drawTextOn(r, fontUI, startLabel, sdl.Color{R: 255, G: 255, B: 255, A: 255}, (g.width-int32(w))/2, y)
+}
+
+// PlayingState is the game itself, paused or not. Escape goes back to the
+// title screen when escapeQuits is on.
+type PlayingState struct {
+	g *Game
+}
+
+func (s *PlayingState) HandleEvent(event sdl.Event) { s.g.handlePlayingEvent(event) }
+
+func (s *PlayingState) Update(dt float64) { s.g.update(dt) }
+
+func (s *PlayingState) Render(r *sdl.Renderer) { s.g.renderPlaying() }
@@ -0,0 +1,40 @@
+package main
+
+import "github.com/veandco/go-sdl2/sdl"
+
+const timingSamples = 60
+
+// rollingAverage is the mean of the last timingSamples values added.
+type rollingAverage struct {
+	samples [timingSamples]float64
+	next    int
+	count   int
+	sum     float64
+}
+
+func (r *rollingAverage) Add(v float64) {
+	r.sum += v - r.samples[r.next]
+	r.samples[r.next] = v
+	r.next = (r.next + 1) % len(r.samples)
+	r.count = min(r.count+1, len(r.samples))
+}
+
+func (r *rollingAverage) Value() float64 {
+	if r.count == 0 {
+		return 0
+	}
+	return r.sum / float64(r.count)
+}
+
+// frameTimings are the average milliseconds spent per frame updating,
+// drawing and in renderer.Present, which blocks on vsync or the GPU, and
+// between one present and the next.
+type frameTimings struct {
+	update, render, present, frame rollingAverage
+	lastPresent                    uint64 // performance counter at the last present
+}
+
+// perfMillis converts a span of performance counter ticks to milliseconds.
+func perfMillis(from, to uint64) float64 {
+	return float64(to-from) * 1000 / float64(sdl.GetPerformanceFrequency())
+}
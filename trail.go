@@ -0,0 +1,54 @@
+package main
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// Trail remembers the recent positions of something moving so it can be
+// drawn again at each of them as fading ghosts.
+type Trail struct {
+	points []Vec2 // oldest first
+}
+
+// Push records p as the newest position, forgetting the oldest ones beyond
+// length.
+func (t *Trail) Push(p Vec2, length int) {
+	t.points = append(t.points, p)
+	if extra := len(t.points) - length; extra > 0 {
+		t.points = append(t.points[:0], t.points[extra:]...)
+	}
+}
+
+// Reset forgets the trail, e.g. after a jump that it shouldn't streak
+// across.
+func (t *Trail) Reset() {
+	t.points = t.points[:0]
+}
+
+// Draw copies tex at each remembered position, shifted by offset and as
+// seen through cam, from the oldest to the newest. The newest ghost is
+// drawn at alpha and older ones fade out linearly. The texture's alpha mod
+// is restored afterwards so the live copy is drawn as before.
+func (t *Trail) Draw(r *sdl.Renderer, tex *sdl.Texture, w, h int32, offset Vec2, cam Camera, alpha uint8) {
+	if len(t.points) == 0 {
+		return
+	}
+	mod, err := tex.GetAlphaMod()
+	if err != nil {
+		return
+	}
+	defer tex.SetAlphaMod(mod)
+	n := len(t.points)
+	for i, p := range t.points {
+		a := float64(alpha) * float64(mod) / 255 * float64(i+1) / float64(n)
+		tex.SetAlphaMod(uint8(a))
+		p = p.Add(offset)
+		dst := cam.Apply(sdl.FRect{X: float32(p.X), Y: float32(p.Y), W: float32(w), H: float32(h)})
+		r.CopyF(tex, nil, &dst)
+	}
+}
+
+// setTextTrail turns the title's trail on or off. It starts out empty
+// either way.
+func (g *Game) setTextTrail(on bool) {
+	g.textTrail = on
+	g.textGhosts.Reset()
+}
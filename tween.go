@@ -0,0 +1,72 @@
+package main
+
+import "math"
+
+// Tween animates something over a fixed duration. Each step it calls update
+// with the eased progress from 0 to 1, and done once it has finished.
+type Tween struct {
+	duration float64
+	elapsed  float64
+	ease     func(t float64) float64
+	update   func(progress float64)
+	done     func()
+	finished bool
+}
+
+func NewTween(duration float64, ease func(float64) float64, update func(float64)) *Tween {
+	return &Tween{duration: duration, ease: ease, update: update}
+}
+
+// OnDone sets a function to call when the tween finishes.
+func (t *Tween) OnDone(done func()) *Tween {
+	t.done = done
+	return t
+}
+
+// Step advances the tween by dt seconds and reports whether it finished.
+func (t *Tween) Step(dt float64) bool {
+	if t.finished {
+		return true
+	}
+	t.elapsed = math.Min(t.elapsed+dt, t.duration)
+	progress := 1.0
+	if t.duration > 0 {
+		progress = t.elapsed / t.duration
+	}
+	t.update(t.ease(progress))
+	if t.elapsed < t.duration {
+		return false
+	}
+	t.finished = true
+	if t.done != nil {
+		t.done()
+	}
+	return true
+}
+
+// Finish jumps straight to the end of the tween.
+func (t *Tween) Finish() {
+	t.Step(t.duration)
+}
+
+func easeLinear(t float64) float64 { return t }
+
+func easeOutCubic(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// updateTweens steps the running tweens, dropping those that finished. A
+// tween started from a done callback is kept and first stepped next frame.
+func (g *Game) updateTweens(dt float64) {
+	tweens := g.tweens
+	g.tweens = nil
+	for _, t := range tweens {
+		if !t.Step(dt) {
+			g.tweens = append(g.tweens, t)
+		}
+	}
+}
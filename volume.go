@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/veandco/go-sdl2/mix"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	// volumeStep is how much a press of + or - changes the volume, out of
+	// mix.MAX_VOLUME.
+	volumeStep = 8
+
+	// volumeShowMillis is how long the volume stays on screen after it
+	// changes.
+	volumeShowMillis = 1500
+
+	volumePadding = 8
+)
+
+// setVolume sets the master volume of the music and every channel, 0-128.
+func (g *Game) setVolume(volume int) {
+	g.volume = volume
+	if g.opts.NoAudio {
+		return
+	}
+	mix.VolumeMusic(volume)
+	mix.Volume(-1, volume)
+}
+
+// changeVolume turns the volume up or down by delta, unmuting it, and shows
+// the new level.
+func (g *Game) changeVolume(delta int) {
+	g.muted = false
+	g.setVolume(max(0, min(g.volume+delta, mix.MAX_VOLUME)))
+	g.volumeShown = sdl.GetTicks()
+}
+
+// toggleMute silences everything, or brings back the volume from before it
+// was muted.
+func (g *Game) toggleMute() {
+	if g.muted {
+		g.muted = false
+		g.setVolume(g.mutedVolume)
+	} else {
+		g.muted = true
+		g.mutedVolume = g.volume
+		g.setVolume(0)
+	}
+	g.volumeShown = sdl.GetTicks()
+}
+
+// savedVolume is the volume to keep in a preset: the one from before muting
+// while muted.
+func (g *Game) savedVolume() int {
+	if g.muted {
+		return g.mutedVolume
+	}
+	return g.volume
+}
+
+func (g *Game) volumeVisible() bool {
+	return g.volumeShown != 0 && sdl.GetTicks()-g.volumeShown < volumeShowMillis
+}
+
+// renderVolume shows the volume in a panel at the bottom center.
+func (g *Game) renderVolume() {
+	font := g.font(fontUI)
+	if font == nil {
+		return
+	}
+	label := fmt.Sprintf("Volume: %d%%", g.volume*100/mix.MAX_VOLUME)
+	if g.muted {
+		label = "Muted"
+	}
+	w, _, err := font.SizeUTF8(label)
+	if err != nil {
+		return
+	}
+	panel := sdl.Rect{W: int32(w) + 2*volumePadding, H: g.lineHeight(fontUI) + 2*volumePadding}
+	panel.X, panel.Y = (g.width-panel.W)/2, g.height-panel.H-volumePadding
+	g.drawPanel(panel, 160)
+	g.drawText(fontUI, label, sdl.Color{R: 255, G: 255, B: 255, A: 255}, panel.X+volumePadding, panel.Y+volumePadding)
+}
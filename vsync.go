@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// fallbackRefreshRate is assumed when the display doesn't report its
+// refresh rate.
+const fallbackRefreshRate = 60
+
+// vsyncProbe is what probeVSync found out about presenting frames.
+type vsyncProbe struct {
+	probed   bool
+	active   bool
+	interval float64 // median milliseconds between presents
+	refresh  int     // display refresh rate in Hz
+}
+
+func (p vsyncProbe) String() string {
+	if !p.probed {
+		return "not probed"
+	}
+	state := "inactive"
+	if p.active {
+		state = "active"
+	}
+	return fmt.Sprintf("%s (%.1f ms, %d Hz)", state, p.interval, p.refresh)
+}
+
+// probeVSync presents the given number of blank frames and times them to
+// guess whether Present actually waits for vsync: if it does, presents are
+// about one refresh apart, otherwise they return almost at once. The first
+// present isn't timed since it can be slow for other reasons. It warns when
+// the renderer has vsync but it doesn't seem to be working, which usually
+// means the driver or compositor overrides it and explains tearing.
+func (g *Game) probeVSync(frames int) {
+	refresh := fallbackRefreshRate
+	if mode, err := sdl.GetCurrentDisplayMode(g.display); err == nil && mode.RefreshRate > 0 {
+		refresh = int(mode.RefreshRate)
+	}
+
+	g.renderer.SetDrawColor(0, 0, 0, 255)
+	g.renderer.Clear()
+	g.renderer.Present()
+	last := sdl.GetPerformanceCounter()
+	intervals := make([]float64, frames)
+	for i := range intervals {
+		g.renderer.Clear()
+		g.renderer.Present()
+		now := sdl.GetPerformanceCounter()
+		intervals[i] = perfMillis(last, now)
+		last = now
+	}
+	slices.Sort(intervals)
+	median := intervals[len(intervals)/2]
+
+	// Half a refresh leaves room for presents that come in early, while
+	// still telling them apart from ones that didn't wait at all.
+	period := 1000 / float64(refresh)
+	g.vsync = vsyncProbe{probed: true, active: median >= period/2, interval: median, refresh: refresh}
+	slog.Info("vsync probe", "active", g.vsync.active, "interval", fmt.Sprintf("%.2fms", median), "refresh", refresh)
+	if g.rendererVSync() && !g.vsync.active {
+		slog.Warn("vsync was requested but presents don't seem to wait for it, expect tearing", "interval", fmt.Sprintf("%.2fms", median), "refresh", refresh)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var windowFlagNames = map[string]uint32{
+	"fullscreen":         sdl.WINDOW_FULLSCREEN,
+	"fullscreen-desktop": sdl.WINDOW_FULLSCREEN_DESKTOP,
+	"opengl":             sdl.WINDOW_OPENGL,
+	"vulkan":             sdl.WINDOW_VULKAN,
+	"hidden":             sdl.WINDOW_HIDDEN,
+	"borderless":         sdl.WINDOW_BORDERLESS,
+	"resizable":          sdl.WINDOW_RESIZABLE,
+	"minimized":          sdl.WINDOW_MINIMIZED,
+	"maximized":          sdl.WINDOW_MAXIMIZED,
+	"input-grabbed":      sdl.WINDOW_INPUT_GRABBED,
+	"highdpi":            sdl.WINDOW_ALLOW_HIGHDPI,
+	"always-on-top":      sdl.WINDOW_ALWAYS_ON_TOP,
+	"skip-taskbar":       sdl.WINDOW_SKIP_TASKBAR,
+	"utility":            sdl.WINDOW_UTILITY,
+}
+
+// parseWindowFlags turns a comma separated list like "resizable,highdpi" into
+// the matching sdl.WINDOW_* flags. An empty string means no extra flags.
+func parseWindowFlags(s string) (uint32, error) {
+	var flags uint32
+	for _, token := range strings.Split(s, ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+		if token == "" {
+			continue
+		}
+		flag, ok := windowFlagNames[token]
+		if !ok {
+			return 0, fmt.Errorf("unknown window flag %q", token)
+		}
+		flags |= flag
+	}
+	return flags, nil
+}
+
+// resize lays the game out for a window of w by h. Drawing keeps using
+// window coordinates through the renderer's logical size; the backdrops
+// made for the old size are dropped and everything moving is brought back
+// inside the new bounds.
+func (g *Game) resize(w, h int32) {
+	if w == g.width && h == g.height {
+		return
+	}
+	slog.Debug("window resized", "from", fmt.Sprintf("%dx%d", g.width, g.height), "to", fmt.Sprintf("%dx%d", w, h))
+	g.width, g.height = w, h
+	if err := g.renderer.SetLogicalSize(w, h); err != nil {
+		slog.Warn("could not set logical size", "err", err)
+	}
+	g.freeAfterimage()
+	g.freeEffect()
+	g.blur.clear()
+
+	for _, s := range g.sprites {
+		s.box.X = clampf(s.box.X, 0, max(float64(w)-s.box.W, 0))
+		s.box.Y = clampf(s.box.Y, 0, max(float64(h)-s.box.H, 0))
+	}
+	if g.cfg.TextMode != textMarquee {
+		g.textPos.X = clampf(g.textPos.X, 0, max(float64(w-g.textRect.W), 0))
+	}
+	g.textPos.Y = clampf(g.textPos.Y, 0, max(float64(h-g.textRect.H), 0))
+	g.textRect.X, g.textRect.Y = int32(math.Round(g.textPos.X)), int32(math.Round(g.textPos.Y))
+	g.textGhosts.Reset()
+}
+
+// toggleFullscreen switches between a window of the configured size and
+// borderless fullscreen on the current display. The layout follows through
+// the size change event either way.
+func (g *Game) toggleFullscreen() {
+	var flags uint32
+	if !g.isFullscreen {
+		flags = sdl.WINDOW_FULLSCREEN_DESKTOP
+	}
+	if err := g.window.SetFullscreen(flags); err != nil {
+		slog.Warn("could not toggle fullscreen", "err", err)
+		return
+	}
+	g.isFullscreen = !g.isFullscreen
+	if !g.isFullscreen {
+		g.window.SetSize(g.cfg.WindowWidth, g.cfg.WindowHeight)
+	}
+}
+
+// setAlwaysOnTop keeps the window above all others, e.g. when the demo is
+// used as a desktop widget, or lets other windows cover it again. SDL can
+// only do it from 2.0.16, so with an older one it is logged as unsupported
+// and the window stays as it is.
+func (g *Game) setAlwaysOnTop(on bool) {
+	g.alwaysOnTop = on
+	if !sdl.VERSION_ATLEAST(2, 0, 16) {
+		slog.Warn("always on top is not supported before SDL 2.0.16", "sdl", fmt.Sprintf("%d.%d.%d", sdl.MAJOR_VERSION, sdl.MINOR_VERSION, sdl.PATCHLEVEL))
+		return
+	}
+	g.window.SetAlwaysOnTop(on)
+	slog.Debug("always on top", "on", on)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// gameWindow is a window besides the main one, with its own renderer. Its
+// content is drawn by draw every frame.
+type gameWindow struct {
+	window   *sdl.Window
+	renderer *sdl.Renderer
+	id       uint32
+	draw     func(r *sdl.Renderer)
+}
+
+// openWindow opens an extra window of the given size next to the main one.
+func (g *Game) openWindow(title string, w, h int32, draw func(*sdl.Renderer)) (*gameWindow, error) {
+	window, err := sdl.CreateWindow(title, sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED, w, h, sdl.WINDOW_SHOWN)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating window: %v", err)
+	}
+	id, err := window.GetID()
+	if err != nil {
+		window.Destroy()
+		return nil, fmt.Errorf("Error getting window ID: %v", err)
+	}
+	renderer, err := g.newRenderer(window)
+	if err != nil {
+		window.Destroy()
+		return nil, err
+	}
+	gw := &gameWindow{window: window, renderer: renderer, id: id, draw: draw}
+	g.windows = append(g.windows, gw)
+	return gw, nil
+}
+
+// closeWindow closes an extra window.
+func (g *Game) closeWindow(w *gameWindow) {
+	g.windows = slices.DeleteFunc(g.windows, func(o *gameWindow) bool { return o == w })
+	if g.debugWindow == w {
+		g.debugWindow = nil
+	}
+	w.renderer.Destroy()
+	w.window.Destroy()
+}
+
+func (g *Game) closeWindows() {
+	for len(g.windows) > 0 {
+		g.closeWindow(g.windows[0])
+	}
+}
+
+// extraWindow returns the extra window with the given ID, or nil for the
+// main window.
+func (g *Game) extraWindow(id uint32) *gameWindow {
+	for _, w := range g.windows {
+		if w.id == id {
+			return w
+		}
+	}
+	return nil
+}
+
+// handleWindowEvent handles an event for one of the extra windows, closing
+// it when asked to.
+func (g *Game) handleWindowEvent(w *gameWindow, e *sdl.WindowEvent) {
+	if e.Event == sdl.WINDOWEVENT_CLOSE {
+		g.closeWindow(w)
+	}
+}
+
+// presentWindows draws and presents every extra window.
+func (g *Game) presentWindows() {
+	for _, w := range g.windows {
+		w.renderer.SetDrawColor(0, 0, 0, 255)
+		w.renderer.Clear()
+		w.draw(w.renderer)
+		w.renderer.Present()
+	}
+}
+
+// setDebugWindow opens or closes a window showing the debug overlay's stats.
+func (g *Game) setDebugWindow(on bool) {
+	if on == (g.debugWindow != nil) {
+		return
+	}
+	if !on {
+		g.closeWindow(g.debugWindow)
+		return
+	}
+	lines := int32(len(g.debugLines()))
+	w, err := g.openWindow(windowTitle+" - debug", debugWidth+2*debugPadding, lines*g.debugLineHeight()+2*debugPadding, func(r *sdl.Renderer) {
+		g.drawDebugLines(r, g.debugLines(), debugPadding, debugPadding)
+	})
+	if err != nil {
+		slog.Error("could not open debug window", "err", err)
+		return
+	}
+	g.debugWindow = w
+}
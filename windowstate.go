@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// windowStateFile is where the window geometry is kept between runs, under
+// the user's config directory.
+const windowStateFile = "golang-sdl2-test/window.json"
+
+// windowState is where the window was and how big, in screen coordinates.
+type windowState struct {
+	X int32 `json:"x"`
+	Y int32 `json:"y"`
+	W int32 `json:"w"`
+	H int32 `json:"h"`
+}
+
+func windowStatePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("Error finding the config directory: %v", err)
+	}
+	return filepath.Join(dir, windowStateFile), nil
+}
+
+// loadWindowState reads the window geometry saved by the last run.
+func loadWindowState() (windowState, error) {
+	var s windowState
+	path, err := windowStatePath()
+	if err != nil {
+		return s, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("Error parsing %s: %v", path, err)
+	}
+	if s.W <= 0 || s.H <= 0 {
+		return s, fmt.Errorf("Error parsing %s: size %dx%d is not positive", path, s.W, s.H)
+	}
+	return s, nil
+}
+
+// saveWindowState writes where the window is and how big for the next run.
+// A fullscreen or maximized window isn't saved, so the next run opens at
+// the geometry it had before.
+func (g *Game) saveWindowState() error {
+	if g.isFullscreen || g.window.GetFlags()&sdl.WINDOW_MAXIMIZED != 0 {
+		return nil
+	}
+	var s windowState
+	s.X, s.Y = g.window.GetPosition()
+	s.W, s.H = g.window.GetSize()
+	path, err := windowStatePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("Error encoding window state: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("Error creating %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("Error saving window state: %v", err)
+	}
+	slog.Debug("window state saved", "path", path, "state", s)
+	return nil
+}
+
+// onScreen reports whether any of the window would be on a display, which
+// it may not be after the display it was on was disconnected.
+func (s windowState) onScreen() bool {
+	n, err := sdl.GetNumVideoDisplays()
+	if err != nil {
+		return false
+	}
+	r := sdl.Rect{X: s.X, Y: s.Y, W: s.W, H: s.H}
+	for i := 0; i < n; i++ {
+		bounds, err := sdl.GetDisplayBounds(i)
+		if err == nil && rectsOverlap(&r, &bounds) {
+			return true
+		}
+	}
+	return false
+}
+
+// initialWindowGeometry is where to open the window: where the last run
+// left it when rememberWindow is on and that is still on a display, or else
+// centered at the configured size. A size given with -width or -height is
+// kept either way.
+func (g *Game) initialWindowGeometry() (x, y, w, h int32) {
+	x, y, w, h = sdl.WINDOWPOS_CENTERED, sdl.WINDOWPOS_CENTERED, g.cfg.WindowWidth, g.cfg.WindowHeight
+	if !g.cfg.RememberWindow {
+		return
+	}
+	s, err := loadWindowState()
+	switch {
+	case os.IsNotExist(err):
+		return
+	case err != nil:
+		slog.Warn("could not restore the window position", "err", err)
+		return
+	case !s.onScreen():
+		slog.Info("saved window position is off screen, centering the window", "state", s)
+		return
+	}
+	x, y = s.X, s.Y
+	if g.opts.Width == 0 {
+		w = s.W
+	}
+	if g.opts.Height == 0 {
+		h = s.H
+	}
+	return
+}